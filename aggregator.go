@@ -0,0 +1,145 @@
+package stopwatch
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+type Stats struct {
+	Count  int
+	Min    time.Duration
+	Max    time.Duration
+	Mean   time.Duration
+	StdDev time.Duration
+	P50    time.Duration
+	P90    time.Duration
+	P99    time.Duration
+	P999   time.Duration
+}
+
+//Aggregator keeps every sample so its percentiles are exact; memory grows
+//with the number of splits added.
+type Aggregator struct {
+	total  *samples
+	splits map[string]*samples
+	names  []string
+
+	al *sync.Mutex
+}
+
+//samples tracks mean and variance incrementally (Welford) and sorts lazily
+//when percentiles are asked for.
+type samples struct {
+	values []time.Duration
+	sorted bool
+	mean   float64
+	m2     float64
+}
+
+func NewAggregator() *Aggregator {
+	return &Aggregator{
+		total:  &samples{},
+		splits: make(map[string]*samples),
+		names:  make([]string, 0),
+
+		al: &sync.Mutex{},
+	}
+}
+
+//Add records the report's Duration toward Total and every split toward its
+//name; a split repeated within one run contributes one sample per lap.
+func (a *Aggregator) Add(r Report) {
+	a.al.Lock()
+	defer a.al.Unlock()
+
+	a.total.add(r.Duration)
+	for _, split := range r.Splits {
+		s, ok := a.splits[split.Name]
+		if !ok {
+			s = &samples{}
+			a.splits[split.Name] = s
+			a.names = append(a.names, split.Name)
+		}
+		s.add(split.Duration)
+	}
+}
+
+func (a *Aggregator) Split(name string) (Stats, bool) {
+	a.al.Lock()
+	defer a.al.Unlock()
+
+	s, ok := a.splits[name]
+	if !ok {
+		return Stats{}, false
+	}
+
+	return s.stats(), true
+}
+
+func (a *Aggregator) Total() Stats {
+	a.al.Lock()
+	defer a.al.Unlock()
+
+	return a.total.stats()
+}
+
+//Names lists split names in the order they were first seen.
+func (a *Aggregator) Names() []string {
+	a.al.Lock()
+	defer a.al.Unlock()
+
+	names := make([]string, len(a.names))
+	copy(names, a.names)
+	return names
+}
+
+func (s *samples) add(d time.Duration) {
+	s.values = append(s.values, d)
+	s.sorted = false
+
+	delta := float64(d) - s.mean
+	s.mean += delta / float64(len(s.values))
+	s.m2 += delta * (float64(d) - s.mean)
+}
+
+func (s *samples) stats() Stats {
+	n := len(s.values)
+	if n == 0 {
+		return Stats{}
+	}
+	if !s.sorted {
+		sort.Slice(s.values, func(i, j int) bool { return s.values[i] < s.values[j] })
+		s.sorted = true
+	}
+
+	return Stats{
+		Count:  n,
+		Min:    s.values[0],
+		Max:    s.values[n-1],
+		Mean:   time.Duration(math.Round(s.mean)),
+		StdDev: time.Duration(math.Round(math.Sqrt(s.m2 / float64(n)))),
+		P50:    percentile(s.values, 0.5),
+		P90:    percentile(s.values, 0.9),
+		P99:    percentile(s.values, 0.99),
+		P999:   percentile(s.values, 0.999),
+	}
+}
+
+//percentile uses the nearest-rank method on already sorted durations.
+func percentile(sorted []time.Duration, q float64) time.Duration {
+	if len(sorted) == 0 {
+		return time.Duration(0)
+	}
+
+	rank := int(math.Ceil(q*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+
+	return sorted[rank]
+}
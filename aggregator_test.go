@@ -0,0 +1,140 @@
+package stopwatch
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"sync"
+	"testing"
+	"time"
+)
+
+//Success
+// - Runs of the shared fixture
+// - Exact percentiles
+// - Repeated split counts every lap
+// - Empty aggregator
+// - Concurrent Add
+func TestAggregator(t *testing.T) {
+	as := new(aggregatorSuite)
+	suite.Run(t, as)
+}
+
+type aggregatorSuite struct {
+	a *Aggregator
+	suite.Suite
+}
+
+func (as *aggregatorSuite) SetupTest() {
+	as.a = NewAggregator()
+}
+
+func (as *aggregatorSuite) TestAggregator_Success() {
+	for _, rpt := range fixtureReports(4) {
+		as.a.Add(rpt)
+	}
+
+	assert.Equal(as.T(), []string{"start", "db", "render"}, as.a.Names())
+
+	total := as.a.Total()
+	assert.Equal(as.T(), 4, total.Count)
+	assert.Equal(as.T(), 10*time.Millisecond, total.Min)
+	assert.Equal(as.T(), 13*time.Millisecond, total.Max)
+	assert.Equal(as.T(), 11500*time.Microsecond, total.Mean)
+
+	db, ok := as.a.Split("db")
+	assert.True(as.T(), ok)
+	assert.Equal(as.T(), 4, db.Count)
+	assert.Equal(as.T(), 8500*time.Microsecond, db.Mean)
+	assert.InDelta(as.T(), float64(1118034*time.Nanosecond), float64(db.StdDev), 1)
+	assert.Equal(as.T(), 8*time.Millisecond, db.P50)
+	assert.Equal(as.T(), 10*time.Millisecond, db.P99)
+
+	render, ok := as.a.Split("render")
+	assert.True(as.T(), ok)
+	assert.Equal(as.T(), 2*time.Millisecond, render.Mean)
+	assert.Zero(as.T(), render.StdDev)
+
+	_, ok = as.a.Split("missing")
+	assert.False(as.T(), ok)
+}
+
+func (as *aggregatorSuite) TestAggregator_Success_Percentiles() {
+	for i := 1; i <= 1000; i++ {
+		as.a.Add(Report{
+			Duration: time.Duration(i) * time.Millisecond,
+			Splits:   []Split{{Name: "db", Duration: time.Duration(i) * time.Microsecond}},
+		})
+	}
+
+	total := as.a.Total()
+	assert.Equal(as.T(), 1000, total.Count)
+	assert.Equal(as.T(), time.Millisecond, total.Min)
+	assert.Equal(as.T(), time.Second, total.Max)
+	assert.Equal(as.T(), 500500*time.Microsecond, total.Mean)
+	assert.InDelta(as.T(), float64(288675*time.Microsecond), float64(total.StdDev), float64(time.Microsecond))
+	assert.Equal(as.T(), 500*time.Millisecond, total.P50)
+	assert.Equal(as.T(), 900*time.Millisecond, total.P90)
+	assert.Equal(as.T(), 990*time.Millisecond, total.P99)
+	assert.Equal(as.T(), 999*time.Millisecond, total.P999)
+
+	db, _ := as.a.Split("db")
+	assert.Equal(as.T(), 990*time.Microsecond, db.P99)
+}
+
+func (as *aggregatorSuite) TestAggregator_Success_RepeatedSplit() {
+	rpt := fixtureReports(1)[0]
+	rpt.Splits = append(rpt.Splits, Split{Name: "db", Duration: 9 * time.Millisecond})
+	as.a.Add(rpt)
+
+	db, _ := as.a.Split("db")
+	assert.Equal(as.T(), 2, db.Count)
+	assert.Equal(as.T(), 8*time.Millisecond, db.Mean)
+	assert.Equal(as.T(), time.Millisecond, db.StdDev)
+	assert.Equal(as.T(), 1, as.a.Total().Count)
+}
+
+func (as *aggregatorSuite) TestAggregator_Success_Empty() {
+	assert.Equal(as.T(), Stats{}, as.a.Total())
+	assert.Empty(as.T(), as.a.Names())
+}
+
+func (as *aggregatorSuite) TestAggregator_Success_Concurrency() {
+	rpt := fixtureReports(1)[0]
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				as.a.Add(rpt)
+				as.a.Split("db")
+			}
+		}()
+	}
+	wg.Wait()
+
+	db, _ := as.a.Split("db")
+	assert.Equal(as.T(), 800, db.Count)
+	assert.Equal(as.T(), 800, as.a.Total().Count)
+}
+
+func TestPercentile(t *testing.T) {
+	sorted := []time.Duration{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	assert.Equal(t, time.Duration(1), percentile(sorted, 0))
+	assert.Equal(t, time.Duration(5), percentile(sorted, 0.5))
+	assert.Equal(t, time.Duration(9), percentile(sorted, 0.9))
+	assert.Equal(t, time.Duration(10), percentile(sorted, 0.99))
+	assert.Equal(t, time.Duration(10), percentile(sorted, 1))
+	assert.Zero(t, percentile(nil, 0.5))
+
+	//the rank rounds up, so counts that leave a fraction never under-report
+	nine := []time.Duration{1, 2, 3, 4, 5, 6, 7, 8, 9}
+	assert.Equal(t, time.Duration(9), percentile(nine, 0.9))
+	assert.Equal(t, time.Duration(5), percentile(nine, 0.5))
+	nineteen := make([]time.Duration, 19)
+	for i := range nineteen {
+		nineteen[i] = time.Duration(i + 1)
+	}
+	assert.Equal(t, time.Duration(18), percentile(nineteen, 0.9))
+	assert.Equal(t, time.Duration(19), percentile(nineteen, 0.99))
+}
@@ -0,0 +1,59 @@
+package stopwatch
+
+import (
+	"sync"
+	"time"
+)
+
+type Clock interface {
+	Now() time.Time
+	Since(t time.Time) time.Duration
+}
+
+//systemClock hands out time.Now() untouched so the monotonic reading survives;
+//durations are then immune to wall clock steps (NTP, manual changes).
+type systemClock struct{}
+
+func (c *systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (c *systemClock) Since(t time.Time) time.Duration {
+	return time.Since(t)
+}
+
+type ManualClock struct {
+	now time.Time
+
+	tl *sync.Mutex
+}
+
+func NewManualClock(now time.Time) *ManualClock {
+	return &ManualClock{
+		now: now,
+
+		tl: &sync.Mutex{},
+	}
+}
+
+func (c *ManualClock) Now() time.Time {
+	c.tl.Lock()
+	defer c.tl.Unlock()
+	return c.now
+}
+
+func (c *ManualClock) Since(t time.Time) time.Duration {
+	return c.Now().Sub(t)
+}
+
+func (c *ManualClock) Advance(d time.Duration) {
+	c.tl.Lock()
+	defer c.tl.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func (c *ManualClock) Set(now time.Time) {
+	c.tl.Lock()
+	defer c.tl.Unlock()
+	c.now = now
+}
@@ -0,0 +1,33 @@
+package stopwatch
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestSystemClock(t *testing.T) {
+	c := &systemClock{}
+	now := c.Now()
+	assert.InDelta(t, time.Now().UnixNano(), now.UnixNano(), nanoTsDelta)
+
+	//monotonic reading is kept, Round(0) strips it
+	assert.NotEqual(t, now.String(), now.Round(0).String())
+	assert.True(t, c.Since(now) >= 0)
+}
+
+func TestManualClock(t *testing.T) {
+	begin := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewManualClock(begin)
+	assert.Equal(t, begin, c.Now())
+	assert.Zero(t, c.Since(begin))
+
+	c.Advance(time.Second)
+	assert.Equal(t, begin.Add(time.Second), c.Now())
+	assert.Equal(t, time.Second, c.Since(begin))
+
+	later := begin.Add(time.Hour)
+	c.Set(later)
+	assert.Equal(t, later, c.Now())
+	assert.Equal(t, time.Hour, c.Since(begin))
+}
@@ -0,0 +1,160 @@
+package stopwatch
+
+import "time"
+
+type Verdict int
+
+const (
+	Unchanged Verdict = iota
+	Improved
+	Regressed
+)
+
+func (v Verdict) String() string {
+	switch v {
+	case Improved:
+		return "improved"
+	case Regressed:
+		return "regressed"
+	}
+
+	return "unchanged"
+}
+
+//A change only counts once it exceeds both Absolute and Relative (a
+//fraction, 0.05 for 5%); the zero Tolerance flags any change at all.
+type Tolerance struct {
+	Absolute time.Duration
+	Relative float64
+}
+
+type SplitDelta struct {
+	Name     string
+	Base     time.Duration
+	Head     time.Duration
+	Delta    time.Duration
+	Relative float64
+	Added    bool
+	Removed  bool
+	Verdict  Verdict
+}
+
+type Comparison struct {
+	Total   SplitDelta
+	Splits  []SplitDelta
+	Added   []string
+	Removed []string
+	Verdict Verdict
+}
+
+type profile struct {
+	names  []string
+	splits map[string]time.Duration
+	total  time.Duration
+}
+
+//Compare sums repeated splits by name in each report before comparing.
+func Compare(base, head Report, tol Tolerance) Comparison {
+	return compareProfiles(newProfile([]Report{base}), newProfile([]Report{head}), tol)
+}
+
+//CompareSets compares the mean per-run duration of every split name, taken
+//over the runs in which the split appears.
+func CompareSets(base, head []Report, tol Tolerance) Comparison {
+	return compareProfiles(newProfile(base), newProfile(head), tol)
+}
+
+func newProfile(reports []Report) profile {
+	p := profile{
+		names:  make([]string, 0),
+		splits: make(map[string]time.Duration),
+	}
+	counts := make(map[string]int)
+	for _, rpt := range reports {
+		p.total += rpt.Duration
+		for _, sum := range rpt.Summary() {
+			if _, seen := p.splits[sum.Name]; !seen {
+				p.names = append(p.names, sum.Name)
+			}
+			p.splits[sum.Name] += sum.Total
+			counts[sum.Name]++
+		}
+	}
+
+	for name, count := range counts {
+		p.splits[name] /= time.Duration(count)
+	}
+	if len(reports) > 0 {
+		p.total /= time.Duration(len(reports))
+	}
+
+	return p
+}
+
+func compareProfiles(base, head profile, tol Tolerance) Comparison {
+	c := Comparison{
+		Total:   newSplitDelta("", base.total, head.total, tol),
+		Splits:  make([]SplitDelta, 0, len(base.names)),
+		Added:   make([]string, 0),
+		Removed: make([]string, 0),
+	}
+
+	for _, name := range base.names {
+		headDur, inHead := head.splits[name]
+		if !inHead {
+			d := newSplitDelta(name, base.splits[name], 0, Tolerance{})
+			d.Removed, d.Verdict = true, Unchanged
+			c.Splits = append(c.Splits, d)
+			c.Removed = append(c.Removed, name)
+			continue
+		}
+		c.Splits = append(c.Splits, newSplitDelta(name, base.splits[name], headDur, tol))
+	}
+
+	for _, name := range head.names {
+		if _, inBase := base.splits[name]; inBase {
+			continue
+		}
+		d := newSplitDelta(name, 0, head.splits[name], Tolerance{})
+		d.Added, d.Verdict = true, Unchanged
+		c.Splits = append(c.Splits, d)
+		c.Added = append(c.Added, name)
+	}
+
+	c.Verdict = c.Total.Verdict
+	for _, d := range c.Splits {
+		if d.Verdict == Regressed {
+			c.Verdict = Regressed
+		}
+	}
+
+	return c
+}
+
+func newSplitDelta(name string, base, head time.Duration, tol Tolerance) SplitDelta {
+	d := SplitDelta{
+		Name:  name,
+		Base:  base,
+		Head:  head,
+		Delta: head - base,
+	}
+	if base != 0 {
+		d.Relative = float64(d.Delta) / float64(base)
+	}
+
+	abs, rel := d.Delta, d.Relative
+	if abs < 0 {
+		abs, rel = -abs, -rel
+	}
+	//with no base to scale against only the absolute tolerance applies
+	withinRelative := base != 0 && rel <= tol.Relative
+	if abs <= tol.Absolute || withinRelative || d.Delta == 0 {
+		d.Verdict = Unchanged
+	} else if d.Delta > 0 {
+		d.Verdict = Regressed
+	} else {
+		d.Verdict = Improved
+	}
+
+	return d
+}
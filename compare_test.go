@@ -0,0 +1,113 @@
+package stopwatch
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+//Success
+// - Deltas, added and removed splits
+// - Within tolerance
+// - Split regression outweighs total improvement
+// - Sets compare mean per run
+// - Empty sets
+func TestCompare(t *testing.T) {
+	cs := new(compareSuite)
+	suite.Run(t, cs)
+}
+
+type compareSuite struct {
+	base Report
+	head Report
+	suite.Suite
+}
+
+func (cs *compareSuite) SetupTest() {
+	reports := fixtureReports(2)
+	cs.base, cs.head = reports[0], reports[1]
+}
+
+func (cs *compareSuite) TestCompare_Success() {
+	cs.base.Splits = append(cs.base.Splits,
+		Split{Name: "db", Duration: time.Millisecond},
+		Split{Name: "cache", Duration: time.Millisecond},
+	)
+	cs.base.Duration = 12 * time.Millisecond
+	cs.head.Splits[1].Duration = 6 * time.Millisecond
+	cs.head.Splits = append(cs.head.Splits, Split{Name: "retry", Duration: 2 * time.Millisecond})
+	cs.head.Duration = 14 * time.Millisecond
+
+	c := Compare(cs.base, cs.head, Tolerance{Relative: 0.1})
+	assert.Equal(cs.T(), []string{"retry"}, c.Added)
+	assert.Equal(cs.T(), []string{"cache"}, c.Removed)
+	if assert.Len(cs.T(), c.Splits, 5) {
+		assert.Equal(cs.T(), "start", c.Splits[0].Name)
+		assert.Equal(cs.T(), Unchanged, c.Splits[0].Verdict)
+
+		assert.Equal(cs.T(), "db", c.Splits[1].Name)
+		assert.Equal(cs.T(), 8*time.Millisecond, c.Splits[1].Base)
+		assert.Equal(cs.T(), -2*time.Millisecond, c.Splits[1].Delta)
+		assert.InDelta(cs.T(), -0.25, c.Splits[1].Relative, 1e-9)
+		assert.Equal(cs.T(), Improved, c.Splits[1].Verdict)
+
+		assert.True(cs.T(), c.Splits[3].Removed)
+		assert.True(cs.T(), c.Splits[4].Added)
+		assert.Equal(cs.T(), Unchanged, c.Splits[4].Verdict)
+	}
+	assert.Equal(cs.T(), 2*time.Millisecond, c.Total.Delta)
+	assert.Equal(cs.T(), Regressed, c.Total.Verdict)
+	assert.Equal(cs.T(), Regressed, c.Verdict)
+}
+
+func (cs *compareSuite) TestCompare_Success_WithinTolerance() {
+	//db grows 7ms to 8ms and the total 10ms to 11ms
+	c := Compare(cs.base, cs.head, Tolerance{Relative: 0.15})
+	assert.Equal(cs.T(), Unchanged, c.Verdict)
+
+	//large relative change but within the absolute noise floor
+	c = Compare(cs.base, cs.head, Tolerance{Absolute: time.Millisecond})
+	assert.Equal(cs.T(), Unchanged, c.Verdict)
+
+	c = Compare(cs.base, cs.head, Tolerance{})
+	assert.Equal(cs.T(), Regressed, c.Verdict)
+}
+
+func (cs *compareSuite) TestCompare_Success_SplitRegression() {
+	cs.head.Splits[2].Duration = 500 * time.Microsecond
+	cs.head.Duration = 9500 * time.Microsecond
+
+	c := Compare(cs.base, cs.head, Tolerance{Relative: 0.01})
+	assert.Equal(cs.T(), Improved, c.Total.Verdict)
+	assert.Equal(cs.T(), Regressed, c.Splits[1].Verdict)
+	assert.Equal(cs.T(), Regressed, c.Verdict)
+}
+
+func (cs *compareSuite) TestCompareSets_Success() {
+	base := fixtureReports(2)
+	head := fixtureReports(4)[2:]
+	head[1].Splits = append(head[1].Splits, Split{Name: "retry", Duration: 2 * time.Millisecond})
+
+	c := CompareSets(base, head, Tolerance{Relative: 0.05})
+	assert.Equal(cs.T(), 10500*time.Microsecond, c.Total.Base)
+	assert.Equal(cs.T(), 12500*time.Microsecond, c.Total.Head)
+	assert.Equal(cs.T(), 7500*time.Microsecond, c.Splits[1].Base)
+	assert.Equal(cs.T(), 9500*time.Microsecond, c.Splits[1].Head)
+	assert.Equal(cs.T(), Regressed, c.Splits[1].Verdict)
+	assert.Equal(cs.T(), []string{"retry"}, c.Added)
+	assert.Equal(cs.T(), 2*time.Millisecond, c.Splits[3].Head)
+	assert.Equal(cs.T(), Regressed, c.Verdict)
+}
+
+func (cs *compareSuite) TestCompareSets_Success_Empty() {
+	c := CompareSets(nil, nil, Tolerance{})
+	assert.Empty(cs.T(), c.Splits)
+	assert.Equal(cs.T(), Unchanged, c.Verdict)
+}
+
+func TestVerdict_String(t *testing.T) {
+	assert.Equal(t, "unchanged", Unchanged.String())
+	assert.Equal(t, "improved", Improved.String())
+	assert.Equal(t, "regressed", Regressed.String())
+}
@@ -0,0 +1,179 @@
+package stopwatch
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+var csvHeader = []string{"run", "stopwatch", "started_at", "split", "comment", "start_offset_ns", "duration_ns", "end_offset_ns"}
+
+//WriteCSV writes one row per split. A report without splits still gets a row,
+//with the split columns left empty, so it survives ReadCSV.
+func WriteCSV(w io.Writer, reports ...Report) error {
+	return writeDelimited(w, ',', reports)
+}
+
+func WriteTSV(w io.Writer, reports ...Report) error {
+	return writeDelimited(w, '\t', reports)
+}
+
+func ReadCSV(r io.Reader) ([]Report, error) {
+	return readDelimited(r, ',')
+}
+
+func ReadTSV(r io.Reader) ([]Report, error) {
+	return readDelimited(r, '\t')
+}
+
+func writeDelimited(w io.Writer, comma rune, reports []Report) error {
+	cw := csv.NewWriter(w)
+	cw.Comma = comma
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+
+	for run, rpt := range reports {
+		startedAt := ""
+		if !rpt.StartedAt.IsZero() {
+			startedAt = rpt.StartedAt.Format(time.RFC3339Nano)
+		}
+
+		if len(rpt.Splits) == 0 {
+			row := []string{strconv.Itoa(run), rpt.Name, startedAt, "", "", "", "", ""}
+			if err := cw.Write(row); err != nil {
+				return err
+			}
+			continue
+		}
+
+		var cumulative time.Duration
+		for _, split := range rpt.Splits {
+			//reports built by hand may lack timestamps; fall back to active time
+			offset, end := cumulative, cumulative+split.Duration
+			if !split.Start.IsZero() && !rpt.StartedAt.IsZero() {
+				offset, end = split.Start.Sub(rpt.StartedAt), split.End.Sub(rpt.StartedAt)
+			}
+			cumulative += split.Duration
+
+			row := []string{
+				strconv.Itoa(run),
+				rpt.Name,
+				startedAt,
+				split.Name,
+				split.Comment,
+				strconv.FormatInt(int64(offset), 10),
+				strconv.FormatInt(int64(split.Duration), 10),
+				strconv.FormatInt(int64(end), 10),
+			}
+			if err := cw.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func readDelimited(r io.Reader, comma rune) ([]Report, error) {
+	cr := csv.NewReader(r)
+	cr.Comma = comma
+	cr.FieldsPerRecord = len(csvHeader)
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, NewParseErr(1, "missing header")
+	}
+	if err != nil {
+		return nil, err
+	}
+	for i := range csvHeader {
+		if header[i] != csvHeader[i] {
+			return nil, NewParseErr(1, fmt.Sprintf("unexpected column %q, expected %q", header[i], csvHeader[i]))
+		}
+	}
+
+	reports := make([]Report, 0)
+	currentRun := ""
+	for line := 2; ; line++ {
+		row, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		startedAt, err := parseStartedAt(row, line)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(reports) == 0 || row[0] != currentRun {
+			currentRun = row[0]
+			reports = append(reports, Report{
+				Name:      row[1],
+				StartedAt: startedAt,
+				Splits:    make([]Split, 0),
+			})
+		}
+		if row[6] == "" {
+			continue
+		}
+
+		split, err := parseSplitRow(row, line, startedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		rpt := &reports[len(reports)-1]
+		rpt.Splits = append(rpt.Splits, split)
+		rpt.Duration += split.Duration
+		if !startedAt.IsZero() {
+			rpt.StoppedAt = split.End
+		}
+	}
+
+	return reports, nil
+}
+
+func parseStartedAt(row []string, line int) (time.Time, error) {
+	if row[2] == "" {
+		return time.Time{}, nil
+	}
+
+	ts, err := time.Parse(time.RFC3339Nano, row[2])
+	if err != nil {
+		return time.Time{}, NewParseErr(line, fmt.Sprintf("bad started_at %q", row[2]))
+	}
+	return ts, nil
+}
+
+func parseSplitRow(row []string, line int, startedAt time.Time) (Split, error) {
+	offset, err := strconv.ParseInt(row[5], 10, 64)
+	if err != nil {
+		return Split{}, NewParseErr(line, fmt.Sprintf("bad start_offset_ns %q", row[5]))
+	}
+
+	dur, err := strconv.ParseInt(row[6], 10, 64)
+	if err != nil {
+		return Split{}, NewParseErr(line, fmt.Sprintf("bad duration_ns %q", row[6]))
+	}
+
+	end, err := strconv.ParseInt(row[7], 10, 64)
+	if err != nil {
+		return Split{}, NewParseErr(line, fmt.Sprintf("bad end_offset_ns %q", row[7]))
+	}
+
+	//End comes from its own offset since Duration leaves out paused time
+	var splitStart, splitEnd time.Time
+	if !startedAt.IsZero() {
+		splitStart = startedAt.Add(time.Duration(offset))
+		splitEnd = startedAt.Add(time.Duration(end))
+	}
+
+	return newSplit(row[3], row[4], splitStart, splitEnd, time.Duration(dur)), nil
+}
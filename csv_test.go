@@ -0,0 +1,163 @@
+package stopwatch
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"strings"
+	"testing"
+	"time"
+)
+
+func AssertRoundTripped(t *testing.T, expected []Report, actual []Report) {
+	assert.Equal(t, len(expected), len(actual))
+	for i := range expected {
+		assert.Equal(t, expected[i].Name, actual[i].Name)
+		assert.Equal(t, expected[i].StartedAt, actual[i].StartedAt)
+		assert.Equal(t, expected[i].StoppedAt, actual[i].StoppedAt)
+		assert.Equal(t, expected[i].Duration, actual[i].Duration)
+		assert.Equal(t, expected[i].Splits, actual[i].Splits)
+	}
+}
+
+//Success
+// - WriteCSV
+// - WriteTSV
+// - CSV round trip
+// - TSV round trip
+// - Quoted comment
+// - Report without timestamps
+// - Report without splits
+//Error
+// - Malformed input
+func TestCSV(t *testing.T) {
+	cs := new(csvSuite)
+	suite.Run(t, cs)
+}
+
+type csvSuite struct {
+	reports []Report
+	buf     *bytes.Buffer
+	suite.Suite
+}
+
+func (cs *csvSuite) SetupTest() {
+	cs.reports = fixtureReports(2)
+	cs.buf = &bytes.Buffer{}
+}
+
+func (cs *csvSuite) TestWriteCSV_Success() {
+	err := WriteCSV(cs.buf, cs.reports...)
+	assert.Nil(cs.T(), err)
+
+	expected := strings.Join([]string{
+		"run,stopwatch,started_at,split,comment,start_offset_ns,duration_ns,end_offset_ns",
+		"0,handler,2019-01-01T00:00:00Z,start,,0,1000000,1000000",
+		"0,handler,2019-01-01T00:00:00Z,db,users table,1000000,7000000,9000000",
+		"0,handler,2019-01-01T00:00:00Z,render,,9000000,2000000,11000000",
+		"1,handler,2019-01-01T00:00:00.011Z,start,,0,1000000,1000000",
+		"1,handler,2019-01-01T00:00:00.011Z,db,users table,1000000,8000000,10000000",
+		"1,handler,2019-01-01T00:00:00.011Z,render,,10000000,2000000,12000000",
+		"",
+	}, "\n")
+	assert.Equal(cs.T(), expected, cs.buf.String())
+}
+
+func (cs *csvSuite) TestWriteTSV_Success() {
+	err := WriteTSV(cs.buf, cs.reports[0])
+	assert.Nil(cs.T(), err)
+	lines := strings.Split(cs.buf.String(), "\n")
+	assert.Equal(cs.T(), "run\tstopwatch\tstarted_at\tsplit\tcomment\tstart_offset_ns\tduration_ns\tend_offset_ns", lines[0])
+	assert.Equal(cs.T(), "0\thandler\t2019-01-01T00:00:00Z\tstart\t\t0\t1000000\t1000000", lines[1])
+}
+
+func (cs *csvSuite) TestCSV_Success_RoundTrip() {
+	assert.Nil(cs.T(), WriteCSV(cs.buf, cs.reports...))
+	decoded, err := ReadCSV(cs.buf)
+	assert.Nil(cs.T(), err)
+	AssertRoundTripped(cs.T(), cs.reports, decoded)
+
+	//the db split holds a pause, so its End is not Start + Duration
+	assert.Equal(cs.T(), cs.reports[0].Splits[1].Start.Add(8*time.Millisecond), decoded[0].Splits[1].End)
+}
+
+func (cs *csvSuite) TestTSV_Success_RoundTrip() {
+	assert.Nil(cs.T(), WriteTSV(cs.buf, cs.reports...))
+	decoded, err := ReadTSV(cs.buf)
+	assert.Nil(cs.T(), err)
+	AssertRoundTripped(cs.T(), cs.reports, decoded)
+}
+
+func (cs *csvSuite) TestWriteCSV_Success_QuotedComment() {
+	cs.reports[0].Splits[1].Comment = `users, "active"`
+	assert.Nil(cs.T(), WriteCSV(cs.buf, cs.reports[0]))
+	assert.Contains(cs.T(), cs.buf.String(), `db,"users, ""active""",`)
+
+	decoded, err := ReadCSV(cs.buf)
+	assert.Nil(cs.T(), err)
+	assert.Equal(cs.T(), `users, "active"`, decoded[0].Splits[1].Comment)
+}
+
+func (cs *csvSuite) TestWriteCSV_Success_WithoutTimestamps() {
+	rpt := Report{
+		Name: "manual",
+		Splits: []Split{
+			{Name: "a", Duration: time.Second},
+			{Name: "b", Duration: 2 * time.Second},
+		},
+	}
+	err := WriteCSV(cs.buf, rpt)
+	assert.Nil(cs.T(), err)
+	assert.Contains(cs.T(), cs.buf.String(), "0,manual,,b,,1000000000,2000000000,3000000000")
+
+	decoded, err := ReadCSV(cs.buf)
+	assert.Nil(cs.T(), err)
+	assert.Equal(cs.T(), 1, len(decoded))
+	assert.Equal(cs.T(), 3*time.Second, decoded[0].Duration)
+	assert.True(cs.T(), decoded[0].StartedAt.IsZero())
+	assert.True(cs.T(), decoded[0].Splits[1].End.IsZero())
+}
+
+func (cs *csvSuite) TestWriteCSV_Success_WithoutSplits() {
+	reports := []Report{{Name: "idle", StartedAt: fixtureStart}, cs.reports[0]}
+	assert.Nil(cs.T(), WriteCSV(cs.buf, reports...))
+	assert.Contains(cs.T(), cs.buf.String(), "0,idle,2019-01-01T00:00:00Z,,,,,\n")
+
+	decoded, err := ReadCSV(cs.buf)
+	assert.Nil(cs.T(), err)
+	assert.Equal(cs.T(), 2, len(decoded))
+	assert.Equal(cs.T(), "idle", decoded[0].Name)
+	assert.Equal(cs.T(), fixtureStart, decoded[0].StartedAt)
+	assert.Empty(cs.T(), decoded[0].Splits)
+	AssertRoundTripped(cs.T(), cs.reports[:1], decoded[1:])
+}
+
+func (cs *csvSuite) TestReadCSV_Error() {
+	_, err := ReadCSV(strings.NewReader(""))
+	assert.NotNil(cs.T(), err)
+	assert.Equal(cs.T(), NewParseErr(1, "missing header").Error(), err.Error())
+
+	_, err = ReadCSV(strings.NewReader("run,name,started_at,split,comment,start_offset_ns,duration_ns,end_offset_ns\n"))
+	assert.NotNil(cs.T(), err)
+	assert.IsType(cs.T(), &StopwatchErr{}, err)
+
+	header := strings.Join(csvHeader, ",") + "\n"
+	_, err = ReadCSV(strings.NewReader(header + "0,w,,a,,0,slow,1\n"))
+	assert.NotNil(cs.T(), err)
+	assert.Equal(cs.T(), NewParseErr(2, `bad duration_ns "slow"`).Error(), err.Error())
+
+	_, err = ReadCSV(strings.NewReader(header + "0,w,,a,,0,1,late\n"))
+	assert.NotNil(cs.T(), err)
+	assert.Equal(cs.T(), NewParseErr(2, `bad end_offset_ns "late"`).Error(), err.Error())
+
+	_, err = ReadCSV(strings.NewReader(header + "0,w,yesterday,a,,0,1,1\n"))
+	assert.NotNil(cs.T(), err)
+	assert.Equal(cs.T(), NewParseErr(2, `bad started_at "yesterday"`).Error(), err.Error())
+
+	_, err = ReadCSV(strings.NewReader(header + "0,w\n"))
+	assert.NotNil(cs.T(), err)
+
+	reports, err := ReadCSV(strings.NewReader(header))
+	assert.Nil(cs.T(), err)
+	assert.Empty(cs.T(), reports)
+}
@@ -0,0 +1,433 @@
+package stopwatch
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+const DefaultCompression = 100
+
+const digestEncodingVersion = 1
+
+//maxCompression bounds decoded compressions, which size the Digest's buffer.
+const maxCompression = 1e6
+
+//Digest is a merging t-digest: a few hundred weighted centroids sized so
+//that quantiles near 0 and 1 stay accurate while memory stays bounded by the
+//compression, however many values are added.
+type Digest struct {
+	compression float64
+	centroids   []centroid
+	buffer      []centroid
+	count       float64
+	min         float64
+	max         float64
+
+	dl *sync.Mutex
+}
+
+type centroid struct {
+	mean   float64
+	weight float64
+}
+
+//NewDigest uses DefaultCompression when compression is not positive; higher
+//values trade memory for accuracy.
+func NewDigest(compression float64) *Digest {
+	if compression <= 0 {
+		compression = DefaultCompression
+	}
+
+	return &Digest{
+		compression: compression,
+		centroids:   make([]centroid, 0),
+		buffer:      make([]centroid, 0, bufferSizeFor(compression)),
+		min:         math.Inf(1),
+		max:         math.Inf(-1),
+
+		dl: &sync.Mutex{},
+	}
+}
+
+func (d *Digest) Add(x float64) {
+	d.dl.Lock()
+	defer d.dl.Unlock()
+
+	d.add(centroid{mean: x, weight: 1})
+}
+
+func (d *Digest) AddDuration(dur time.Duration) {
+	d.Add(float64(dur))
+}
+
+func (d *Digest) add(c centroid) {
+	d.buffer = append(d.buffer, c)
+	d.count += c.weight
+	if c.mean < d.min {
+		d.min = c.mean
+	}
+	if c.mean > d.max {
+		d.max = c.mean
+	}
+	if len(d.buffer) >= bufferSizeFor(d.compression) {
+		d.compress()
+	}
+}
+
+//Merge folds other's centroids into d; other is left untouched.
+func (d *Digest) Merge(other *Digest) {
+	other.dl.Lock()
+	incoming := make([]centroid, 0, len(other.centroids)+len(other.buffer))
+	incoming = append(incoming, other.centroids...)
+	incoming = append(incoming, other.buffer...)
+	otherMin, otherMax := other.min, other.max
+	other.dl.Unlock()
+
+	d.dl.Lock()
+	defer d.dl.Unlock()
+
+	for _, c := range incoming {
+		d.add(c)
+	}
+	//centroid means lie inside other's range, so restore its true extremes
+	if otherMin < d.min {
+		d.min = otherMin
+	}
+	if otherMax > d.max {
+		d.max = otherMax
+	}
+}
+
+func (d *Digest) Count() int64 {
+	d.dl.Lock()
+	defer d.dl.Unlock()
+
+	return int64(d.count)
+}
+
+//Quantile interpolates between centroid centres for q in [0, 1]; an empty
+//digest returns 0.
+func (d *Digest) Quantile(q float64) float64 {
+	d.dl.Lock()
+	defer d.dl.Unlock()
+
+	d.compress()
+	if d.count == 0 {
+		return 0
+	}
+	if q <= 0 {
+		return d.min
+	}
+	if q >= 1 {
+		return d.max
+	}
+	if len(d.centroids) == 1 {
+		return d.centroids[0].mean
+	}
+
+	index := q * d.count
+	first := d.centroids[0]
+	if index < first.weight/2 {
+		return d.min + (first.mean-d.min)*index/(first.weight/2)
+	}
+
+	before := 0.0
+	for i := 0; i < len(d.centroids)-1; i++ {
+		left, right := d.centroids[i], d.centroids[i+1]
+		leftCentre := before + left.weight/2
+		rightCentre := before + left.weight + right.weight/2
+		if index < rightCentre {
+			return left.mean + (right.mean-left.mean)*(index-leftCentre)/(rightCentre-leftCentre)
+		}
+		before += left.weight
+	}
+
+	last := d.centroids[len(d.centroids)-1]
+	lastCentre := d.count - last.weight/2
+	return last.mean + (d.max-last.mean)*(index-lastCentre)/(d.count-lastCentre)
+}
+
+func (d *Digest) QuantileDuration(q float64) time.Duration {
+	return time.Duration(math.Round(d.Quantile(q)))
+}
+
+//compress sorts the buffered values into the centroids, merging neighbours
+//while they fit within one unit of the k1 scale function.
+func (d *Digest) compress() {
+	if len(d.buffer) == 0 {
+		return
+	}
+
+	all := append(d.centroids, d.buffer...)
+	sort.Slice(all, func(i, j int) bool { return all[i].mean < all[j].mean })
+
+	merged := make([]centroid, 0, len(d.centroids)+1)
+	merged = append(merged, all[0])
+	before := 0.0
+	for _, c := range all[1:] {
+		cur := &merged[len(merged)-1]
+		if d.scale((before+cur.weight+c.weight)/d.count)-d.scale(before/d.count) <= 1 {
+			cur.weight += c.weight
+			cur.mean += (c.mean - cur.mean) * c.weight / cur.weight
+			continue
+		}
+		before += cur.weight
+		merged = append(merged, c)
+	}
+
+	d.centroids = merged
+	d.buffer = d.buffer[:0]
+}
+
+func (d *Digest) scale(q float64) float64 {
+	if q > 1 {
+		q = 1
+	}
+	return d.compression / (2 * math.Pi) * math.Asin(2*q-1)
+}
+
+func validCompression(compression float64) bool {
+	return compression > 0 && compression <= maxCompression
+}
+
+func bufferSizeFor(compression float64) int {
+	return int(math.Ceil(compression * 5))
+}
+
+type digestHeader struct {
+	Version     uint32
+	Compression float64
+	Min         float64
+	Max         float64
+	Centroids   uint32
+}
+
+//MarshalBinary compresses d and writes its header followed by each
+//centroid's mean and weight.
+func (d *Digest) MarshalBinary() ([]byte, error) {
+	d.dl.Lock()
+	defer d.dl.Unlock()
+
+	d.compress()
+	buf := &bytes.Buffer{}
+	header := digestHeader{
+		Version:     digestEncodingVersion,
+		Compression: d.compression,
+		Min:         d.min,
+		Max:         d.max,
+		Centroids:   uint32(len(d.centroids)),
+	}
+	if err := binary.Write(buf, binary.BigEndian, header); err != nil {
+		return nil, err
+	}
+	for _, c := range d.centroids {
+		if err := binary.Write(buf, binary.BigEndian, [2]float64{c.mean, c.weight}); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+//UnmarshalBinary replaces d entirely, including its compression.
+func (d *Digest) UnmarshalBinary(data []byte) error {
+	r := bytes.NewReader(data)
+	header := digestHeader{}
+	if err := binary.Read(r, binary.BigEndian, &header); err != nil {
+		return NewCorruptDataErr("short digest header")
+	}
+	if header.Version != digestEncodingVersion {
+		return NewCorruptDataErr(fmt.Sprintf("unsupported digest encoding version %d", header.Version))
+	}
+	if uint64(r.Len()) != uint64(header.Centroids)*16 {
+		return NewCorruptDataErr(fmt.Sprintf("expected %d centroids", header.Centroids))
+	}
+	if !validCompression(header.Compression) {
+		return NewCorruptDataErr(fmt.Sprintf("compression %v out of range", header.Compression))
+	}
+
+	decoded := NewDigest(header.Compression)
+	decoded.min, decoded.max = header.Min, header.Max
+	for n := uint32(0); n < header.Centroids; n++ {
+		pair := [2]float64{}
+		if err := binary.Read(r, binary.BigEndian, &pair); err != nil {
+			return NewCorruptDataErr("truncated centroid")
+		}
+		if math.IsNaN(pair[0]) || !(pair[1] > 0) || math.IsInf(pair[1], 1) {
+			return NewCorruptDataErr(fmt.Sprintf("invalid centroid %d", n))
+		}
+		decoded.centroids = append(decoded.centroids, centroid{mean: pair[0], weight: pair[1]})
+		decoded.count += pair[1]
+	}
+
+	if d.dl == nil {
+		d.dl = &sync.Mutex{}
+	}
+	d.dl.Lock()
+	defer d.dl.Unlock()
+
+	dl := d.dl
+	*d = *decoded
+	d.dl = dl
+	return nil
+}
+
+//DigestSet keeps one Digest of split durations per split name.
+type DigestSet struct {
+	compression float64
+	digests     map[string]*Digest
+	names       []string
+
+	dl *sync.Mutex
+}
+
+func NewDigestSet(compression float64) *DigestSet {
+	if compression <= 0 {
+		compression = DefaultCompression
+	}
+
+	return &DigestSet{
+		compression: compression,
+		digests:     make(map[string]*Digest),
+		names:       make([]string, 0),
+
+		dl: &sync.Mutex{},
+	}
+}
+
+func (s *DigestSet) Record(r Report) {
+	for _, split := range r.Splits {
+		s.digest(split.Name).AddDuration(split.Duration)
+	}
+}
+
+//Merge folds every digest of other into the one of the same name, so sets
+//built in separate processes can be combined after a MarshalBinary round
+//trip.
+func (s *DigestSet) Merge(other *DigestSet) {
+	for _, name := range other.Names() {
+		theirs, _ := other.Digest(name)
+		s.digest(name).Merge(theirs)
+	}
+}
+
+func (s *DigestSet) Digest(name string) (*Digest, bool) {
+	s.dl.Lock()
+	defer s.dl.Unlock()
+
+	d, ok := s.digests[name]
+	return d, ok
+}
+
+//Names lists split names in the order they were first recorded.
+func (s *DigestSet) Names() []string {
+	s.dl.Lock()
+	defer s.dl.Unlock()
+
+	names := make([]string, len(s.names))
+	copy(names, s.names)
+	return names
+}
+
+//MarshalBinary writes the compression and then each name with its encoded
+//digest, both length-prefixed.
+func (s *DigestSet) MarshalBinary() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if err := binary.Write(buf, binary.BigEndian, s.compression); err != nil {
+		return nil, err
+	}
+
+	names := s.Names()
+	scratch := make([]byte, binary.MaxVarintLen64)
+	buf.Write(scratch[:binary.PutUvarint(scratch, uint64(len(names)))])
+	for _, name := range names {
+		d, _ := s.Digest(name)
+		data, err := d.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(scratch[:binary.PutUvarint(scratch, uint64(len(name)))])
+		buf.WriteString(name)
+		buf.Write(scratch[:binary.PutUvarint(scratch, uint64(len(data)))])
+		buf.Write(data)
+	}
+	return buf.Bytes(), nil
+}
+
+//UnmarshalBinary replaces s entirely; every digest must share the set's
+//compression.
+func (s *DigestSet) UnmarshalBinary(data []byte) error {
+	r := bytes.NewReader(data)
+	var compression float64
+	if err := binary.Read(r, binary.BigEndian, &compression); err != nil {
+		return NewCorruptDataErr("short digest set header")
+	}
+	if !validCompression(compression) {
+		return NewCorruptDataErr(fmt.Sprintf("compression %v out of range", compression))
+	}
+	count, err := binary.ReadUvarint(r)
+	if err != nil {
+		return NewCorruptDataErr("short digest set header")
+	}
+
+	decoded := NewDigestSet(compression)
+	for n := uint64(0); n < count; n++ {
+		name, err := readPrefixed(r, "truncated digest set entry")
+		if err != nil {
+			return err
+		}
+		encoded, err := readPrefixed(r, "truncated digest set entry")
+		if err != nil {
+			return err
+		}
+		d := &Digest{}
+		if err := d.UnmarshalBinary(encoded); err != nil {
+			return err
+		}
+		if d.compression != decoded.compression {
+			return NewCorruptDataErr(fmt.Sprintf("digest %q does not match the set's compression", name))
+		}
+		decoded.digests[string(name)] = d
+		decoded.names = append(decoded.names, string(name))
+	}
+
+	if s.dl == nil {
+		s.dl = &sync.Mutex{}
+	}
+	s.dl.Lock()
+	defer s.dl.Unlock()
+
+	dl := s.dl
+	*s = *decoded
+	s.dl = dl
+	return nil
+}
+
+func (s *DigestSet) digest(name string) *Digest {
+	s.dl.Lock()
+	defer s.dl.Unlock()
+
+	d, ok := s.digests[name]
+	if !ok {
+		d = NewDigest(s.compression)
+		s.digests[name] = d
+		s.names = append(s.names, name)
+	}
+	return d
+}
+
+//readPrefixed reads a uvarint length and that many bytes, failing with a
+//CorruptDataErr for reason when r runs short.
+func readPrefixed(r *bytes.Reader, reason string) ([]byte, error) {
+	n, err := binary.ReadUvarint(r)
+	if err != nil || n > uint64(r.Len()) {
+		return nil, NewCorruptDataErr(reason)
+	}
+	b := make([]byte, n)
+	r.Read(b)
+	return b, nil
+}
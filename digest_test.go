@@ -0,0 +1,230 @@
+package stopwatch
+
+import (
+	"encoding/binary"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"math"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+)
+
+//Success
+// - Quantiles within the k1 rank error
+// - Centroids stay bounded
+// - Small and empty digests
+// - Merge
+// - Binary round trip
+// - Concurrent add and merge
+// - Set records fixture splits by name
+// - Set merges across a binary round trip
+//Error
+// - Corrupt encoding
+// - Corrupt set encoding
+func TestDigest(t *testing.T) {
+	ds := new(digestSuite)
+	suite.Run(t, ds)
+}
+
+type digestSuite struct {
+	d       *Digest
+	set     *DigestSet
+	reports []Report
+	suite.Suite
+}
+
+func (ds *digestSuite) SetupTest() {
+	ds.d = NewDigest(DefaultCompression)
+	ds.set = NewDigestSet(DefaultCompression)
+	ds.reports = fixtureReports(4)
+}
+
+func (ds *digestSuite) TestQuantile_Success() {
+	rnd := rand.New(rand.NewSource(1))
+	for _, i := range rnd.Perm(100000) {
+		ds.d.Add(float64(i + 1))
+	}
+
+	assert.Equal(ds.T(), int64(100000), ds.d.Count())
+	for _, q := range []float64{0.01, 0.1, 0.5, 0.9, 0.99, 0.999} {
+		//rank error shrinks towards the tails under the k1 scale
+		tolerance := 0.01 * math.Sqrt(q*(1-q)) * 100000
+		assert.InDelta(ds.T(), q*100000, ds.d.Quantile(q), tolerance, "quantile %v", q)
+	}
+	assert.Equal(ds.T(), 1.0, ds.d.Quantile(0))
+	assert.Equal(ds.T(), 100000.0, ds.d.Quantile(1))
+}
+
+func (ds *digestSuite) TestQuantile_Success_BoundedCentroids() {
+	d := NewDigest(50)
+	for i := 0; i < 200000; i++ {
+		d.Add(float64(i % 1000))
+	}
+	d.Quantile(0.5)
+	assert.True(ds.T(), len(d.centroids) <= 100, "got %d centroids", len(d.centroids))
+}
+
+func (ds *digestSuite) TestQuantile_Success_SmallAndEmpty() {
+	assert.Zero(ds.T(), ds.d.Quantile(0.5))
+
+	ds.d.AddDuration(5 * time.Millisecond)
+	assert.Equal(ds.T(), 5*time.Millisecond, ds.d.QuantileDuration(0.5))
+	assert.Equal(ds.T(), 5*time.Millisecond, ds.d.QuantileDuration(0.99))
+
+	ds.d.AddDuration(7 * time.Millisecond)
+	assert.Equal(ds.T(), 5*time.Millisecond, ds.d.QuantileDuration(0))
+	assert.Equal(ds.T(), 6*time.Millisecond, ds.d.QuantileDuration(0.5))
+	assert.Equal(ds.T(), 7*time.Millisecond, ds.d.QuantileDuration(1))
+}
+
+func (ds *digestSuite) TestMerge_Success() {
+	other := NewDigest(100)
+	for i := 1; i <= 50000; i++ {
+		ds.d.Add(float64(i))
+		other.Add(float64(i + 50000))
+	}
+
+	ds.d.Merge(other)
+	assert.Equal(ds.T(), int64(100000), ds.d.Count())
+	assert.Equal(ds.T(), int64(50000), other.Count())
+	assert.InDelta(ds.T(), 50000, ds.d.Quantile(0.5), 500)
+	assert.InDelta(ds.T(), 99000, ds.d.Quantile(0.99), 100)
+	assert.Equal(ds.T(), 100000.0, ds.d.Quantile(1))
+}
+
+func (ds *digestSuite) TestBinaryRoundTrip_Success() {
+	d := NewDigest(200)
+	for i := 1; i <= 10000; i++ {
+		d.AddDuration(time.Duration(i) * time.Microsecond)
+	}
+
+	data, err := d.MarshalBinary()
+	assert.Nil(ds.T(), err)
+
+	decoded := &Digest{}
+	assert.Nil(ds.T(), decoded.UnmarshalBinary(data))
+	assert.Equal(ds.T(), d.Count(), decoded.Count())
+	for _, q := range []float64{0, 0.5, 0.9, 0.999, 1} {
+		assert.Equal(ds.T(), d.Quantile(q), decoded.Quantile(q))
+	}
+
+	decoded.Add(0)
+	assert.Equal(ds.T(), 0.0, decoded.Quantile(0))
+}
+
+func (ds *digestSuite) TestConcurrency_Success() {
+	other := NewDigest(100)
+	other.Add(1)
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				ds.d.Add(float64(j))
+				ds.d.Quantile(0.5)
+			}
+			ds.d.Merge(other)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(ds.T(), int64(8008), ds.d.Count())
+}
+
+func (ds *digestSuite) TestDigestSet_Success() {
+	for _, rpt := range ds.reports {
+		ds.set.Record(rpt)
+	}
+
+	assert.Equal(ds.T(), []string{"start", "db", "render"}, ds.set.Names())
+	db, ok := ds.set.Digest("db")
+	assert.True(ds.T(), ok)
+	assert.Equal(ds.T(), int64(4), db.Count())
+	assert.Equal(ds.T(), 7*time.Millisecond, db.QuantileDuration(0))
+	assert.Equal(ds.T(), 8500*time.Microsecond, db.QuantileDuration(0.5))
+	assert.Equal(ds.T(), 10*time.Millisecond, db.QuantileDuration(1))
+
+	_, ok = ds.set.Digest("missing")
+	assert.False(ds.T(), ok)
+}
+
+func (ds *digestSuite) TestDigestSet_Success_MergeAcrossProcesses() {
+	other := NewDigestSet(DefaultCompression)
+	ds.set.Record(ds.reports[0])
+	extra := ds.reports[1]
+	extra.Splits = append(extra.Splits, Split{Name: "flush", Duration: time.Second})
+	other.Record(extra)
+
+	data, err := other.MarshalBinary()
+	assert.Nil(ds.T(), err)
+	received := &DigestSet{}
+	assert.Nil(ds.T(), received.UnmarshalBinary(data))
+	assert.Equal(ds.T(), []string{"start", "db", "render", "flush"}, received.Names())
+
+	ds.set.Merge(received)
+	db, _ := ds.set.Digest("db")
+	assert.Equal(ds.T(), int64(2), db.Count())
+	assert.Equal(ds.T(), 7500*time.Microsecond, db.QuantileDuration(0.5))
+	flush, _ := ds.set.Digest("flush")
+	assert.Equal(ds.T(), time.Second, flush.QuantileDuration(0.5))
+}
+
+func (ds *digestSuite) TestUnmarshalBinary_Error() {
+	d := &Digest{}
+	err := d.UnmarshalBinary([]byte{1})
+	assert.Equal(ds.T(), NewCorruptDataErr("short digest header").Error(), err.Error())
+
+	good := NewDigest(100)
+	good.Add(1)
+	data, _ := good.MarshalBinary()
+	err = d.UnmarshalBinary(data[:len(data)-1])
+	assert.Equal(ds.T(), NewCorruptDataErr("expected 1 centroids").Error(), err.Error())
+
+	data[3] = 2
+	err = d.UnmarshalBinary(data)
+	assert.Equal(ds.T(), NewCorruptDataErr("unsupported digest encoding version 2").Error(), err.Error())
+	data[3] = digestEncodingVersion
+
+	for _, compression := range []float64{math.NaN(), math.Inf(1), -1, 0, 1e300} {
+		corrupt := append([]byte(nil), data...)
+		binary.BigEndian.PutUint64(corrupt[4:], math.Float64bits(compression))
+		err = d.UnmarshalBinary(corrupt)
+		assert.Equal(ds.T(), NewCorruptDataErr(fmt.Sprintf("compression %v out of range", compression)).Error(), err.Error())
+	}
+
+	for _, weight := range []float64{math.NaN(), math.Inf(1), -1, 0} {
+		corrupt := append([]byte(nil), data...)
+		binary.BigEndian.PutUint64(corrupt[40:], math.Float64bits(weight))
+		err = d.UnmarshalBinary(corrupt)
+		assert.Equal(ds.T(), NewCorruptDataErr("invalid centroid 0").Error(), err.Error(), "weight %v", weight)
+	}
+
+	corrupt := append([]byte(nil), data...)
+	binary.BigEndian.PutUint64(corrupt[32:], math.Float64bits(math.NaN()))
+	err = d.UnmarshalBinary(corrupt)
+	assert.Equal(ds.T(), NewCorruptDataErr("invalid centroid 0").Error(), err.Error())
+}
+
+func (ds *digestSuite) TestDigestSet_Error_UnmarshalBinary() {
+	ds.set.Record(ds.reports[0])
+	data, _ := ds.set.MarshalBinary()
+
+	err := (&DigestSet{}).UnmarshalBinary(data[:12])
+	assert.Equal(ds.T(), NewCorruptDataErr("truncated digest set entry").Error(), err.Error())
+	err = (&DigestSet{}).UnmarshalBinary(data[:4])
+	assert.Equal(ds.T(), NewCorruptDataErr("short digest set header").Error(), err.Error())
+
+	binary.BigEndian.PutUint64(data, math.Float64bits(math.NaN()))
+	err = (&DigestSet{}).UnmarshalBinary(data)
+	assert.Equal(ds.T(), NewCorruptDataErr("compression NaN out of range").Error(), err.Error())
+
+	mismatched := NewDigestSet(DefaultCompression)
+	mismatched.digests["db"] = NewDigest(50)
+	mismatched.names = append(mismatched.names, "db")
+	data, _ = mismatched.MarshalBinary()
+	err = (&DigestSet{}).UnmarshalBinary(data)
+	assert.Equal(ds.T(), NewCorruptDataErr(`digest "db" does not match the set's compression`).Error(), err.Error())
+}
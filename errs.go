@@ -34,6 +34,36 @@ func NewNotStoppedErr(w *Stopwatch) *StopwatchErr {
 	}
 }
 
+func NewAlreadyPausedErr(w *Stopwatch) *StopwatchErr {
+	return &StopwatchErr{
+		msg: fmt.Sprintf("stopwatch %s has already been paused", w.Name),
+	}
+}
+
+func NewNotPausedErr(w *Stopwatch) *StopwatchErr {
+	return &StopwatchErr{
+		msg: fmt.Sprintf("stopwatch %s has not been paused", w.Name),
+	}
+}
+
+
+func NewAlreadyJoinedErr(w *Stopwatch, branchName string) *StopwatchErr {
+	return &StopwatchErr{
+		msg: fmt.Sprintf("stopwatch %s branch %s has already been joined", w.Name, branchName),
+	}
+}
+
+func NewIntervalOpenErr(w *Stopwatch, intervalName string) *StopwatchErr {
+	return &StopwatchErr{
+		msg: fmt.Sprintf("stopwatch %s interval %s is already open", w.Name, intervalName),
+	}
+}
+
+func NewIntervalNotOpenErr(w *Stopwatch, intervalName string) *StopwatchErr {
+	return &StopwatchErr{
+		msg: fmt.Sprintf("stopwatch %s interval %s is not open", w.Name, intervalName),
+	}
+}
 
 func NewNotFoundErr() *StopwatchErr {
 	return &StopwatchErr{
@@ -47,8 +77,55 @@ func NewNonExistentKeyErr(w *Stopwatch, missingKey key) *StopwatchErr {
 	}
 }
 
+func NewInvalidKeyErr(w *Stopwatch, badKey string, reason string) *StopwatchErr {
+	return &StopwatchErr{
+		msg: fmt.Sprintf("stopwatch %s cannot use key %q: %s", w.Name, badKey, reason),
+	}
+}
+
+func NewUnsupportedVersionErr(version int) *StopwatchErr {
+	return &StopwatchErr{
+		msg: fmt.Sprintf("unsupported report schema version %d", version),
+	}
+}
+
+func NewParseErr(line int, reason string) *StopwatchErr {
+	return &StopwatchErr{
+		msg: fmt.Sprintf("line %d: %s", line, reason),
+	}
+}
+
 func NewBadValueErr(be interface{}) *StopwatchErr {
 	return &StopwatchErr{
 		msg: fmt.Sprintf("found unexpected type in context: %T", be),
 	}
-}
\ No newline at end of file
+}
+func NewInvalidHistogramErr(reason string) *StopwatchErr {
+	return &StopwatchErr{
+		msg: fmt.Sprintf("invalid histogram: %s", reason),
+	}
+}
+
+func NewValueOutOfRangeErr(value, lowest, highest int64) *StopwatchErr {
+	return &StopwatchErr{
+		msg: fmt.Sprintf("value %d outside trackable range [%d, %d]", value, lowest, highest),
+	}
+}
+
+func NewCorruptDataErr(reason string) *StopwatchErr {
+	return &StopwatchErr{
+		msg: fmt.Sprintf("corrupt encoded data: %s", reason),
+	}
+}
+
+func NewInvalidWindowErr(reason string) *StopwatchErr {
+	return &StopwatchErr{
+		msg: fmt.Sprintf("invalid rolling window: %s", reason),
+	}
+}
+
+func NewInvalidQuantileErr(q float64) *StopwatchErr {
+	return &StopwatchErr{
+		msg: fmt.Sprintf("quantile %v outside [0, 1]", q),
+	}
+}
@@ -0,0 +1,80 @@
+package stopwatch
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var frameReplacer = strings.NewReplacer(";", ":", "\n", " ", "\r", " ")
+
+type FoldedStacks struct {
+	stacks map[string]time.Duration
+
+	fl *sync.Mutex
+}
+
+func NewFoldedStacks() *FoldedStacks {
+	return &FoldedStacks{
+		stacks: make(map[string]time.Duration),
+
+		fl: &sync.Mutex{},
+	}
+}
+
+//Add folds every split into a "stopwatch;split" stack; child stopwatches
+//stack under their parent as "parent;child;split".
+func (f *FoldedStacks) Add(r Report) {
+	f.fl.Lock()
+	defer f.fl.Unlock()
+
+	f.add("", r)
+}
+
+func (f *FoldedStacks) add(prefix string, r Report) {
+	stack := prefix + frameReplacer.Replace(r.Name)
+	for _, split := range r.Splits {
+		f.stacks[stack+";"+frameReplacer.Replace(split.Name)] += split.Duration
+	}
+
+	for _, child := range r.Children {
+		f.add(stack+";", child)
+	}
+}
+
+//WriteTo writes one "frame;frame value" line per stack, sorted, with values
+//in nanoseconds as expected by flamegraph.pl and speedscope.
+func (f *FoldedStacks) WriteTo(w io.Writer) (int64, error) {
+	f.fl.Lock()
+	defer f.fl.Unlock()
+
+	stacks := make([]string, 0, len(f.stacks))
+	for stack := range f.stacks {
+		stacks = append(stacks, stack)
+	}
+	sort.Strings(stacks)
+
+	var written int64
+	for _, stack := range stacks {
+		n, err := fmt.Fprintf(w, "%s %d\n", stack, int64(f.stacks[stack]))
+		written += int64(n)
+		if err != nil {
+			return written, err
+		}
+	}
+
+	return written, nil
+}
+
+func WriteFolded(w io.Writer, reports ...Report) error {
+	f := NewFoldedStacks()
+	for _, rpt := range reports {
+		f.Add(rpt)
+	}
+
+	_, err := f.WriteTo(w)
+	return err
+}
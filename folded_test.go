@@ -0,0 +1,90 @@
+package stopwatch
+
+import (
+	"bytes"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type failingWriter struct{}
+
+func (w *failingWriter) Write(_ []byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+//Success
+// - Children nest under their parent
+// - Repeated splits are summed
+// - Concurrent Add
+//Error
+// - Write error
+func TestFoldedStacks(t *testing.T) {
+	fs := new(foldedSuite)
+	suite.Run(t, fs)
+}
+
+type foldedSuite struct {
+	reports []Report
+	buf     *bytes.Buffer
+	suite.Suite
+}
+
+func (fs *foldedSuite) SetupTest() {
+	fs.reports = fixtureReports(2)
+	fs.buf = &bytes.Buffer{}
+}
+
+func (fs *foldedSuite) TestWriteFolded_Success() {
+	err := WriteFolded(fs.buf, fs.reports...)
+	assert.Nil(fs.T(), err)
+
+	expected := strings.Join([]string{
+		"handler;db 15000000",
+		"handler;db;query:select 13000000",
+		"handler;db;start 4000000",
+		"handler;render 4000000",
+		"handler;start 2000000",
+		"",
+	}, "\n")
+	assert.Equal(fs.T(), expected, fs.buf.String())
+}
+
+func (fs *foldedSuite) TestFoldedStacks_Success_RepeatedSplits() {
+	rpt := fs.reports[0]
+	rpt.Children = nil
+	rpt.Splits = append(rpt.Splits, Split{Name: "db", Duration: time.Millisecond})
+
+	f := NewFoldedStacks()
+	f.Add(rpt)
+	n, err := f.WriteTo(fs.buf)
+	assert.Nil(fs.T(), err)
+	assert.Equal(fs.T(), int64(fs.buf.Len()), n)
+	assert.Contains(fs.T(), fs.buf.String(), "handler;db 8000000\n")
+}
+
+func (fs *foldedSuite) TestFoldedStacks_Success_ConcurrentAdd() {
+	f := NewFoldedStacks()
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			f.Add(fs.reports[0])
+		}()
+	}
+	wg.Wait()
+
+	_, _ = f.WriteTo(fs.buf)
+	assert.Contains(fs.T(), fs.buf.String(), "handler;db 56000000\n")
+}
+
+func (fs *foldedSuite) TestWriteFolded_Error_Write() {
+	err := WriteFolded(&failingWriter{}, fs.reports...)
+	assert.NotNil(fs.T(), err)
+	assert.Equal(fs.T(), "disk full", err.Error())
+}
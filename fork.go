@@ -0,0 +1,172 @@
+package stopwatch
+
+import (
+	"sort"
+	"time"
+)
+
+type Branch struct {
+	Name string
+
+	w   *Stopwatch
+	run int
+	idx int
+}
+
+type Parallelism struct {
+	CriticalPath         []Interval
+	CriticalPathDuration time.Duration
+	Work                 time.Duration
+	Span                 time.Duration
+	Effective            float64
+}
+
+func (w *Stopwatch) Fork(name string) (*Branch, error) {
+	var b *Branch
+	err := w.stamp(func() (record, error) {
+		if w.stopped() {
+			return record{}, NewAlreadyStoppedErr(w)
+		}
+
+		if !w.started() {
+			return record{}, NewNotStartedErr(w)
+		}
+
+		ts := w.Clock.Now()
+		w.branches = append(w.branches, namedInterval{
+			name:     name,
+			interval: interval{begin: ts},
+		})
+		b = &Branch{
+			Name: name,
+			w:    w,
+			run:  w.run,
+			idx:  len(w.branches) - 1,
+		}
+		return newRecord(fork, ts, name), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+func (b *Branch) Join() error {
+	w := b.w
+	return w.stamp(func() (record, error) {
+		//a branch forked before Reset belongs to a run that is over
+		if w.stopped() || b.run != w.run {
+			return record{}, NewAlreadyStoppedErr(w)
+		}
+
+		if !w.branches[b.idx].end.IsZero() {
+			return record{}, NewAlreadyJoinedErr(w, b.Name)
+		}
+
+		ts := w.Clock.Now()
+		w.branches[b.idx].end = ts
+		return newRecord(join, ts, b.Name), nil
+	})
+}
+
+//Parallelism treats a branch that starts after another has joined as
+//depending on it; the critical path is the longest such chain.
+func (r Report) Parallelism() Parallelism {
+	p := Parallelism{
+		CriticalPath: make([]Interval, 0),
+	}
+	if len(r.Branches) == 0 {
+		return p
+	}
+
+	for _, b := range r.Branches {
+		p.Work += b.Duration
+	}
+	p.Span = r.branchSpan()
+	if p.Span > 0 {
+		p.Effective = float64(p.Work) / float64(p.Span)
+	}
+
+	p.CriticalPath = criticalPath(r.Branches)
+	for _, b := range p.CriticalPath {
+		p.CriticalPathDuration += b.Duration
+	}
+
+	return p
+}
+
+func criticalPath(branches []Interval) []Interval {
+	byEnd := make([]Interval, len(branches))
+	copy(byEnd, branches)
+	sort.SliceStable(byEnd, func(i, j int) bool {
+		return byEnd[i].End.Before(byEnd[j].End)
+	})
+
+	longest := make([]time.Duration, len(byEnd))
+	prev := make([]int, len(byEnd))
+	last := 0
+	for i, b := range byEnd {
+		longest[i], prev[i] = b.Duration, -1
+		for j := 0; j < i; j++ {
+			if byEnd[j].End.After(b.Start) {
+				continue
+			}
+			if longest[j]+b.Duration > longest[i] {
+				longest[i], prev[i] = longest[j]+b.Duration, j
+			}
+		}
+		if longest[i] > longest[last] {
+			last = i
+		}
+	}
+
+	path := make([]Interval, 0)
+	for i := last; i >= 0; i = prev[i] {
+		path = append([]Interval{byEnd[i]}, path...)
+	}
+
+	return path
+}
+
+//branchSpan is the active time during which at least one branch was running.
+func (r Report) branchSpan() time.Duration {
+	byStart := make([]Interval, len(r.Branches))
+	copy(byStart, r.Branches)
+	sort.SliceStable(byStart, func(i, j int) bool {
+		return byStart[i].Start.Before(byStart[j].Start)
+	})
+
+	var span time.Duration
+	segStart, segEnd := byStart[0].Start, byStart[0].End
+	for _, b := range byStart[1:] {
+		if b.Start.After(segEnd) {
+			span += r.activeBetween(segStart, segEnd)
+			segStart, segEnd = b.Start, b.End
+			continue
+		}
+		if b.End.After(segEnd) {
+			segEnd = b.End
+		}
+	}
+
+	return span + r.activeBetween(segStart, segEnd)
+}
+
+func (r Report) activeBetween(begin, end time.Time) time.Duration {
+	active := end.Sub(begin)
+	for _, p := range r.Pauses {
+		from, to := p.Start, p.End
+		if from.Before(begin) {
+			from = begin
+		}
+		if to.After(end) {
+			to = end
+		}
+		if to.After(from) {
+			active -= to.Sub(from)
+		}
+	}
+
+	return active
+}
@@ -0,0 +1,174 @@
+package stopwatch
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"sync"
+	"testing"
+	"time"
+)
+
+//Success
+// - Branches reported
+// - Unjoined branch ends at stop
+// - Concurrent branches
+//Error
+// - NotStarted
+// - AlreadyStopped
+// - AlreadyJoined
+func TestFork(t *testing.T) {
+	fs := new(forkSuite)
+	suite.Run(t, fs)
+}
+
+type forkSuite struct {
+	w      *Stopwatch
+	clock  *ManualClock
+	logger *testLogger
+	suite.Suite
+}
+
+func (fs *forkSuite) SetupTest() {
+	fs.logger = &testLogger{}
+	fs.clock = NewManualClock(time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC))
+	fs.w = New("test", fs.logger, fs.clock)
+}
+
+func (fs *forkSuite) TestFork_Success() {
+	_ = fs.w.Start()
+	users, err := fs.w.Fork("users")
+	assert.Nil(fs.T(), err)
+	assert.Equal(fs.T(), "users", users.Name)
+	orders, _ := fs.w.Fork("orders")
+	fs.clock.Advance(2 * time.Second)
+	assert.Nil(fs.T(), orders.Join())
+	fs.clock.Advance(time.Second)
+	assert.Nil(fs.T(), users.Join())
+	_ = fs.w.Stop()
+
+	rpt, err := fs.w.Report()
+	assert.Nil(fs.T(), err)
+	assert.Equal(fs.T(), 2, len(rpt.Branches))
+	assert.Equal(fs.T(), "users", rpt.Branches[0].Name)
+	assert.Equal(fs.T(), 3*time.Second, rpt.Branches[0].Duration)
+	assert.Equal(fs.T(), "orders", rpt.Branches[1].Name)
+	assert.Equal(fs.T(), 2*time.Second, rpt.Branches[1].Duration)
+
+	expectedNumOfLogs := 6
+	assert.Equal(fs.T(), expectedNumOfLogs, len(fs.logger.logs))
+	assert.Equal(fs.T(), fork.String(), fs.logger.logs[1].key)
+	assert.Equal(fs.T(), "users", fs.logger.logs[1].comment)
+	assert.Equal(fs.T(), join.String(), fs.logger.logs[3].key)
+	assert.Equal(fs.T(), "orders", fs.logger.logs[3].comment)
+}
+
+func (fs *forkSuite) TestFork_Success_UnjoinedBranch() {
+	_ = fs.w.Start()
+	_, _ = fs.w.Fork("users")
+	fs.clock.Advance(time.Second)
+	_ = fs.w.Stop()
+
+	rpt, err := fs.w.Report()
+	assert.Nil(fs.T(), err)
+	assert.Equal(fs.T(), 1, len(rpt.Branches))
+	assert.Equal(fs.T(), time.Second, rpt.Branches[0].Duration)
+	assert.Equal(fs.T(), fs.clock.Now(), rpt.Branches[0].End)
+}
+
+func (fs *forkSuite) TestFork_Success_Concurrent() {
+	w := New("test", fs.logger, nil)
+	_ = w.Start()
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 16; i++ {
+		b, err := w.Fork("worker")
+		assert.Nil(fs.T(), err)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = b.Join()
+		}()
+	}
+	wg.Wait()
+	_ = w.Stop()
+
+	rpt, err := w.Report()
+	assert.Nil(fs.T(), err)
+	assert.Equal(fs.T(), 16, len(rpt.Branches))
+}
+
+func (fs *forkSuite) TestFork_Error_NotStarted() {
+	b, err := fs.w.Fork("users")
+	assert.Nil(fs.T(), b)
+	assert.NotNil(fs.T(), err)
+	assert.IsType(fs.T(), &StopwatchErr{}, err)
+	assert.Equal(fs.T(), NewNotStartedErr(fs.w).Error(), err.Error())
+}
+
+func (fs *forkSuite) TestFork_Error_AlreadyStopped() {
+	_ = fs.w.Start()
+	b, _ := fs.w.Fork("users")
+	_ = fs.w.Stop()
+
+	err := b.Join()
+	assert.NotNil(fs.T(), err)
+	assert.Equal(fs.T(), NewAlreadyStoppedErr(fs.w).Error(), err.Error())
+
+	b, err = fs.w.Fork("orders")
+	assert.Nil(fs.T(), b)
+	assert.NotNil(fs.T(), err)
+	assert.Equal(fs.T(), NewAlreadyStoppedErr(fs.w).Error(), err.Error())
+}
+
+func (fs *forkSuite) TestJoin_Error_AlreadyJoined() {
+	_ = fs.w.Start()
+	b, _ := fs.w.Fork("users")
+	assert.Nil(fs.T(), b.Join())
+
+	err := b.Join()
+	assert.NotNil(fs.T(), err)
+	assert.IsType(fs.T(), &StopwatchErr{}, err)
+	assert.Equal(fs.T(), NewAlreadyJoinedErr(fs.w, "users").Error(), err.Error())
+}
+
+func TestReportParallelism(t *testing.T) {
+	begin := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(sec int) time.Time {
+		return begin.Add(time.Duration(sec) * time.Second)
+	}
+	branch := func(name string, from, to int) Interval {
+		return Interval{Name: name, Start: at(from), End: at(to), Duration: at(to).Sub(at(from))}
+	}
+
+	//auth runs alone, then users/orders fan out, then render after both
+	rpt := Report{
+		Branches: []Interval{
+			branch("auth", 0, 2),
+			branch("users", 2, 6),
+			branch("orders", 2, 5),
+			branch("render", 6, 7),
+		},
+	}
+
+	p := rpt.Parallelism()
+	assert.Equal(t, 10*time.Second, p.Work)
+	assert.Equal(t, 7*time.Second, p.Span)
+	assert.InDelta(t, 10.0/7.0, p.Effective, 1e-9)
+	assert.Equal(t, 7*time.Second, p.CriticalPathDuration)
+	names := make([]string, len(p.CriticalPath))
+	for i, b := range p.CriticalPath {
+		names[i] = b.Name
+	}
+	assert.Equal(t, []string{"auth", "users", "render"}, names)
+
+	//a pause during the fan-out is not counted as parallel span
+	rpt.Pauses = []Interval{branch("pause", 3, 4)}
+	assert.Equal(t, 6*time.Second, rpt.Parallelism().Span)
+}
+
+func TestReportParallelism_NoBranches(t *testing.T) {
+	p := Report{}.Parallelism()
+	assert.Zero(t, p.Work)
+	assert.Zero(t, p.Span)
+	assert.Zero(t, p.Effective)
+	assert.Empty(t, p.CriticalPath)
+}
@@ -0,0 +1,140 @@
+package stopwatch
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const (
+	defaultGanttWidth = 60
+	minGanttWidth     = 10
+)
+
+const (
+	ganttBar    = '='
+	ganttPaused = '.'
+	ganttEmpty  = ' '
+	ganttRule   = '-'
+	ganttLap    = '^'
+)
+
+type GanttOptions struct {
+	Width        int
+	HideComments bool
+}
+
+type ganttSpan struct {
+	from time.Duration
+	to   time.Duration
+}
+
+//WriteGantt draws one bar per split on a timeline scaled to Width columns.
+//Paused time shows as '.', laps are marked with '^' on the ruler below and
+//commented splits get a '*' followed by the comment.
+func WriteGantt(w io.Writer, r Report, opts GanttOptions) error {
+	width := opts.Width
+	if width == 0 {
+		width = defaultGanttWidth
+	}
+	if width < minGanttWidth {
+		width = minGanttWidth
+	}
+
+	spans, total := ganttSpans(r)
+	nameWidth := len(r.Name)
+	for _, split := range r.Splits {
+		if len(split.Name) > nameWidth {
+			nameWidth = len(split.Name)
+		}
+	}
+
+	//column maps an offset to [0, width]; width itself is the exclusive end
+	column := func(offset time.Duration) int {
+		if total <= 0 {
+			return 0
+		}
+		col := int(float64(offset) / float64(total) * float64(width))
+		if col > width {
+			col = width
+		}
+		if col < 0 {
+			col = 0
+		}
+		return col
+	}
+	startColumn := func(offset time.Duration) int {
+		col := column(offset)
+		if col == width {
+			col--
+		}
+		return col
+	}
+
+	if _, err := fmt.Fprintf(w, "%-*s  %s\n", nameWidth, r.Name, r.Duration); err != nil {
+		return err
+	}
+
+	for i, split := range r.Splits {
+		bar := []rune(strings.Repeat(string(ganttEmpty), width))
+		from, to := startColumn(spans[i].from), column(spans[i].to)
+		if to <= from {
+			to = from + 1
+		}
+		for col := from; col < to; col++ {
+			bar[col] = ganttBar
+		}
+		if !r.StartedAt.IsZero() {
+			for _, p := range r.Pauses {
+				pFrom, pTo := column(p.Start.Sub(r.StartedAt)), column(p.End.Sub(r.StartedAt))
+				for col := pFrom; col < pTo; col++ {
+					if col >= from && col < to {
+						bar[col] = ganttPaused
+					}
+				}
+			}
+		}
+
+		line := fmt.Sprintf("%-*s  |%s|  %s", nameWidth, split.Name, string(bar), split.Duration)
+		if split.Comment != "" && !opts.HideComments {
+			line += "  * " + split.Comment
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+	}
+
+	ruler := []rune(strings.Repeat(string(ganttRule), width))
+	for i := 1; i < len(spans); i++ {
+		ruler[startColumn(spans[i].from)] = ganttLap
+	}
+	_, err := fmt.Fprintf(w, "%-*s  +%s+\n", nameWidth, "", string(ruler))
+	return err
+}
+
+//ganttSpans places splits by their timestamps, or back to back by duration
+//for reports that were built without them.
+func ganttSpans(r Report) ([]ganttSpan, time.Duration) {
+	spans := make([]ganttSpan, len(r.Splits))
+	if !r.StartedAt.IsZero() {
+		for i, split := range r.Splits {
+			spans[i] = ganttSpan{
+				from: split.Start.Sub(r.StartedAt),
+				to:   split.End.Sub(r.StartedAt),
+			}
+		}
+		return spans, reportEnd(r).Sub(r.StartedAt)
+	}
+
+	var cumulative time.Duration
+	for i, split := range r.Splits {
+		spans[i] = ganttSpan{
+			from: cumulative,
+			to:   cumulative + split.Duration,
+		}
+		cumulative += split.Duration
+	}
+
+	return spans, cumulative
+}
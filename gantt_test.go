@@ -0,0 +1,95 @@
+package stopwatch
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"strings"
+	"testing"
+	"time"
+)
+
+//Success
+// - Paused time and comments
+// - HideComments
+// - Default and minimum width
+// - Report without timestamps
+// - Empty report
+func TestWriteGantt(t *testing.T) {
+	gs := new(ganttSuite)
+	suite.Run(t, gs)
+}
+
+type ganttSuite struct {
+	rpt Report
+	buf *bytes.Buffer
+	suite.Suite
+}
+
+func (gs *ganttSuite) SetupTest() {
+	gs.rpt = fixtureReports(1)[0]
+	gs.buf = &bytes.Buffer{}
+}
+
+func (gs *ganttSuite) TestWriteGantt_Success() {
+	//11ms of wall time at one column per millisecond
+	err := WriteGantt(gs.buf, gs.rpt, GanttOptions{Width: 11})
+	assert.Nil(gs.T(), err)
+
+	expected := strings.Join([]string{
+		"handler  10ms",
+		"start    |=          |  1ms",
+		"db       | ====.===  |  7ms  * users table",
+		"render   |         ==|  2ms",
+		"         +-^-------^-+",
+		"",
+	}, "\n")
+	assert.Equal(gs.T(), expected, gs.buf.String())
+}
+
+func (gs *ganttSuite) TestWriteGantt_Success_HideComments() {
+	err := WriteGantt(gs.buf, gs.rpt, GanttOptions{Width: 11, HideComments: true})
+	assert.Nil(gs.T(), err)
+	assert.NotContains(gs.T(), gs.buf.String(), "users")
+}
+
+func (gs *ganttSuite) TestWriteGantt_Success_DefaultWidth() {
+	err := WriteGantt(gs.buf, gs.rpt, GanttOptions{})
+	assert.Nil(gs.T(), err)
+	lines := strings.Split(gs.buf.String(), "\n")
+	assert.Equal(gs.T(), len("         +")+defaultGanttWidth+1, len(lines[4]))
+
+	gs.buf.Reset()
+	_ = WriteGantt(gs.buf, gs.rpt, GanttOptions{Width: 3})
+	lines = strings.Split(gs.buf.String(), "\n")
+	assert.Equal(gs.T(), len("         +")+minGanttWidth+1, len(lines[4]))
+}
+
+func (gs *ganttSuite) TestWriteGantt_Success_WithoutTimestamps() {
+	rpt := Report{
+		Name:     "manual",
+		Duration: 4 * time.Second,
+		Splits: []Split{
+			{Name: "a", Duration: time.Second},
+			{Name: "b", Duration: 3 * time.Second},
+		},
+	}
+
+	err := WriteGantt(gs.buf, rpt, GanttOptions{Width: 12})
+	assert.Nil(gs.T(), err)
+
+	expected := strings.Join([]string{
+		"manual  4s",
+		"a       |===         |  1s",
+		"b       |   =========|  3s",
+		"        +---^--------+",
+		"",
+	}, "\n")
+	assert.Equal(gs.T(), expected, gs.buf.String())
+}
+
+func (gs *ganttSuite) TestWriteGantt_Success_Empty() {
+	err := WriteGantt(gs.buf, Report{Name: "empty"}, GanttOptions{Width: 10})
+	assert.Nil(gs.T(), err)
+	assert.Equal(gs.T(), "empty  0s\n       +----------+\n", gs.buf.String())
+}
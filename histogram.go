@@ -0,0 +1,588 @@
+package stopwatch
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/bits"
+	"sync"
+	"time"
+)
+
+const histogramEncodingVersion = 1
+
+//Histogram is an HDR-style histogram: values from 0 to highest are counted
+//in buckets no wider than 10^-sigfigs of the value they hold, so memory
+//depends only on the range and precision, never on the sample count.
+type Histogram struct {
+	lowest  int64
+	highest int64
+	sigfigs int
+
+	unitMagnitude               uint
+	subBucketHalfCountMagnitude uint
+	subBucketCount              int
+	subBucketHalfCount          int
+	subBucketMask               int64
+
+	counts     []int64
+	totalCount int64
+	min        int64
+	max        int64
+	sum        float64
+
+	hl *sync.Mutex
+}
+
+//NewHistogram tracks values in [0, highest] with sigfigs (1-5) decimal digits
+//of precision. lowest is the smallest value told apart from 0; anything below
+//it shares the first bucket. Durations are recorded in nanoseconds.
+func NewHistogram(lowest, highest int64, sigfigs int) (*Histogram, error) {
+	if lowest < 1 {
+		return nil, NewInvalidHistogramErr("lowest trackable value must be at least 1")
+	}
+	if highest < 2*lowest {
+		return nil, NewInvalidHistogramErr("highest trackable value must be at least twice the lowest")
+	}
+	if sigfigs < 1 || sigfigs > 5 {
+		return nil, NewInvalidHistogramErr("significant figures must be between 1 and 5")
+	}
+
+	largestSingleUnit := 2 * math.Pow10(sigfigs)
+	subBucketCountMagnitude := uint(math.Ceil(math.Log2(largestSingleUnit)))
+	subBucketHalfCountMagnitude := subBucketCountMagnitude - 1
+	unitMagnitude := uint(bits.Len64(uint64(lowest)) - 1)
+	//the first bucket's range must still fit in an int64 at this precision
+	if unitMagnitude+subBucketHalfCountMagnitude > 61 {
+		return nil, NewInvalidHistogramErr("lowest trackable value is too large for the significant figures")
+	}
+	subBucketCount := 1 << (subBucketHalfCountMagnitude + 1)
+
+	bucketCount := 1
+	smallestUntrackable := int64(subBucketCount) << unitMagnitude
+	for smallestUntrackable <= highest {
+		if smallestUntrackable > math.MaxInt64/2 {
+			bucketCount++
+			break
+		}
+		smallestUntrackable <<= 1
+		bucketCount++
+	}
+
+	return &Histogram{
+		lowest:  lowest,
+		highest: highest,
+		sigfigs: sigfigs,
+
+		unitMagnitude:               unitMagnitude,
+		subBucketHalfCountMagnitude: subBucketHalfCountMagnitude,
+		subBucketCount:              subBucketCount,
+		subBucketHalfCount:          subBucketCount / 2,
+		subBucketMask:               int64(subBucketCount-1) << unitMagnitude,
+
+		counts: make([]int64, (bucketCount+1)*(subBucketCount/2)),
+		min:    math.MaxInt64,
+
+		hl: &sync.Mutex{},
+	}, nil
+}
+
+func (h *Histogram) RecordValue(v int64) error {
+	return h.RecordValues(v, 1)
+}
+
+func (h *Histogram) RecordDuration(d time.Duration) error {
+	return h.RecordValues(int64(d), 1)
+}
+
+//RecordValues records n occurrences of v at once.
+func (h *Histogram) RecordValues(v, n int64) error {
+	h.hl.Lock()
+	defer h.hl.Unlock()
+
+	return h.record(v, n)
+}
+
+func (h *Histogram) record(v, n int64) error {
+	if v < 0 || v > h.highest {
+		return NewValueOutOfRangeErr(v, 0, h.highest)
+	}
+
+	h.counts[h.countsIndexFor(v)] += n
+	h.totalCount += n
+	h.sum += float64(v) * float64(n)
+	if v < h.min {
+		h.min = v
+	}
+	if v > h.max {
+		h.max = v
+	}
+	return nil
+}
+
+//Merge adds every count in other, which may use a different range or
+//precision; buckets outside this histogram's range are dropped and reported
+//as an error once the rest have been merged.
+func (h *Histogram) Merge(other *Histogram) error {
+	other.hl.Lock()
+	values := make(map[int64]int64)
+	for i, count := range other.counts {
+		if count != 0 {
+			values[other.valueFromIndex(i)] += count
+		}
+	}
+	otherCount, otherMin, otherMax, otherSum := other.totalCount, other.min, other.max, other.sum
+	other.hl.Unlock()
+
+	h.hl.Lock()
+	defer h.hl.Unlock()
+
+	var err error
+	//when other's extremes were dropped fall back to the merged buckets
+	mergedMin, mergedMax := int64(math.MaxInt64), int64(0)
+	for v, count := range values {
+		if v > h.highest {
+			if err == nil {
+				err = NewValueOutOfRangeErr(v, 0, h.highest)
+			}
+			otherCount -= count
+			otherSum -= float64(v) * float64(count)
+			continue
+		}
+		h.counts[h.countsIndexFor(v)] += count
+		if v < mergedMin {
+			mergedMin = v
+		}
+		if v > mergedMax {
+			mergedMax = v
+		}
+	}
+	if otherCount == 0 {
+		return err
+	}
+	if otherMin <= h.highest {
+		mergedMin = otherMin
+	}
+	if otherMax <= h.highest {
+		mergedMax = otherMax
+	}
+
+	h.totalCount += otherCount
+	h.sum += otherSum
+	if mergedMin < h.min {
+		h.min = mergedMin
+	}
+	if mergedMax > h.max {
+		h.max = mergedMax
+	}
+	return err
+}
+
+//Quantile returns the value at quantile q (0 to 1), accurate to the
+//histogram's significant figures; an empty histogram returns 0.
+func (h *Histogram) Quantile(q float64) int64 {
+	h.hl.Lock()
+	defer h.hl.Unlock()
+
+	if h.totalCount == 0 {
+		return 0
+	}
+	if q < 0 {
+		q = 0
+	}
+	if q > 1 {
+		q = 1
+	}
+
+	target := int64(q*float64(h.totalCount) + 0.5)
+	if target < 1 {
+		target = 1
+	}
+
+	var seen int64
+	for i, count := range h.counts {
+		seen += count
+		if seen >= target {
+			v := h.highestEquivalentValue(h.valueFromIndex(i))
+			if v > h.max {
+				return h.max
+			}
+			if v < h.min {
+				return h.min
+			}
+			return v
+		}
+	}
+	return h.max
+}
+
+func (h *Histogram) QuantileDuration(q float64) time.Duration {
+	return time.Duration(h.Quantile(q))
+}
+
+func (h *Histogram) TotalCount() int64 {
+	h.hl.Lock()
+	defer h.hl.Unlock()
+
+	return h.totalCount
+}
+
+func (h *Histogram) Min() int64 {
+	h.hl.Lock()
+	defer h.hl.Unlock()
+
+	if h.totalCount == 0 {
+		return 0
+	}
+	return h.min
+}
+
+func (h *Histogram) Max() int64 {
+	h.hl.Lock()
+	defer h.hl.Unlock()
+
+	return h.max
+}
+
+func (h *Histogram) Mean() float64 {
+	h.hl.Lock()
+	defer h.hl.Unlock()
+
+	if h.totalCount == 0 {
+		return 0
+	}
+	return h.sum / float64(h.totalCount)
+}
+
+func (h *Histogram) Reset() {
+	h.hl.Lock()
+	defer h.hl.Unlock()
+
+	for i := range h.counts {
+		h.counts[i] = 0
+	}
+	h.totalCount, h.min, h.max, h.sum = 0, math.MaxInt64, 0, 0
+}
+
+type histogramHeader struct {
+	Version    uint32
+	Lowest     int64
+	Highest    int64
+	Sigfigs    uint32
+	TotalCount int64
+	Min        int64
+	Max        int64
+	Sum        float64
+	Buckets    uint32
+}
+
+//MarshalBinary writes a fixed header followed by the non-empty buckets as
+//varint (index, count) pairs.
+func (h *Histogram) MarshalBinary() ([]byte, error) {
+	h.hl.Lock()
+	defer h.hl.Unlock()
+
+	header := histogramHeader{
+		Version:    histogramEncodingVersion,
+		Lowest:     h.lowest,
+		Highest:    h.highest,
+		Sigfigs:    uint32(h.sigfigs),
+		TotalCount: h.totalCount,
+		Min:        h.min,
+		Max:        h.max,
+		Sum:        h.sum,
+	}
+	for _, count := range h.counts {
+		if count != 0 {
+			header.Buckets++
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	if err := binary.Write(buf, binary.BigEndian, header); err != nil {
+		return nil, err
+	}
+	scratch := make([]byte, binary.MaxVarintLen64)
+	for i, count := range h.counts {
+		if count == 0 {
+			continue
+		}
+		buf.Write(scratch[:binary.PutUvarint(scratch, uint64(i))])
+		buf.Write(scratch[:binary.PutUvarint(scratch, uint64(count))])
+	}
+	return buf.Bytes(), nil
+}
+
+//UnmarshalBinary replaces h entirely, including its range and precision.
+func (h *Histogram) UnmarshalBinary(data []byte) error {
+	r := bytes.NewReader(data)
+	header := histogramHeader{}
+	if err := binary.Read(r, binary.BigEndian, &header); err != nil {
+		return NewCorruptDataErr("short histogram header")
+	}
+	if header.Version != histogramEncodingVersion {
+		return NewCorruptDataErr(fmt.Sprintf("unsupported histogram encoding version %d", header.Version))
+	}
+
+	decoded, err := NewHistogram(header.Lowest, header.Highest, int(header.Sigfigs))
+	if err != nil {
+		return err
+	}
+	var total int64
+	for n := uint32(0); n < header.Buckets; n++ {
+		idx, err := binary.ReadUvarint(r)
+		if err != nil {
+			return NewCorruptDataErr("truncated bucket")
+		}
+		count, err := binary.ReadUvarint(r)
+		if err != nil {
+			return NewCorruptDataErr("truncated bucket")
+		}
+		if idx >= uint64(len(decoded.counts)) {
+			return NewCorruptDataErr(fmt.Sprintf("bucket index %d out of range", idx))
+		}
+		if decoded.counts[idx] != 0 {
+			return NewCorruptDataErr(fmt.Sprintf("bucket %d repeated", idx))
+		}
+		if count > math.MaxInt64 || int64(count) > math.MaxInt64-total {
+			return NewCorruptDataErr(fmt.Sprintf("bucket %d count overflows", idx))
+		}
+		decoded.counts[idx] = int64(count)
+		total += int64(count)
+	}
+	if total != header.TotalCount {
+		return NewCorruptDataErr(fmt.Sprintf("bucket counts sum to %d, header says %d", total, header.TotalCount))
+	}
+	if total > 0 && (header.Min < 0 || header.Max > header.Highest || header.Min > header.Max) {
+		return NewCorruptDataErr(fmt.Sprintf("min %d and max %d outside [0, %d]", header.Min, header.Max, header.Highest))
+	}
+	decoded.totalCount = header.TotalCount
+	decoded.min = header.Min
+	decoded.max = header.Max
+	decoded.sum = header.Sum
+
+	if h.hl == nil {
+		h.hl = &sync.Mutex{}
+	}
+	h.hl.Lock()
+	defer h.hl.Unlock()
+
+	hl := h.hl
+	*h = *decoded
+	h.hl = hl
+	return nil
+}
+
+func (h *Histogram) bucketIndex(v int64) int {
+	pow2Ceiling := bits.Len64(uint64(v | h.subBucketMask))
+	return pow2Ceiling - int(h.unitMagnitude) - int(h.subBucketHalfCountMagnitude+1)
+}
+
+func (h *Histogram) subBucketIndex(v int64, bucketIdx int) int {
+	return int(v >> (uint(bucketIdx) + h.unitMagnitude))
+}
+
+func (h *Histogram) countsIndexFor(v int64) int {
+	bucketIdx := h.bucketIndex(v)
+	subBucketIdx := h.subBucketIndex(v, bucketIdx)
+	return (bucketIdx+1)<<h.subBucketHalfCountMagnitude + subBucketIdx - h.subBucketHalfCount
+}
+
+func (h *Histogram) valueFromIndex(i int) int64 {
+	bucketIdx := (i >> h.subBucketHalfCountMagnitude) - 1
+	subBucketIdx := (i & (h.subBucketHalfCount - 1)) + h.subBucketHalfCount
+	if bucketIdx < 0 {
+		subBucketIdx -= h.subBucketHalfCount
+		bucketIdx = 0
+	}
+	return int64(subBucketIdx) << (uint(bucketIdx) + h.unitMagnitude)
+}
+
+func (h *Histogram) highestEquivalentValue(v int64) int64 {
+	bucketIdx := h.bucketIndex(v)
+	subBucketIdx := h.subBucketIndex(v, bucketIdx)
+	lowestEquivalent := int64(subBucketIdx) << (uint(bucketIdx) + h.unitMagnitude)
+	if subBucketIdx >= h.subBucketCount {
+		bucketIdx++
+	}
+	return lowestEquivalent + int64(1)<<(h.unitMagnitude+uint(bucketIdx)) - 1
+}
+
+//HistogramSet keeps one Histogram of split durations per split name, all
+//sharing the same range and precision.
+type HistogramSet struct {
+	lowest  int64
+	highest int64
+	sigfigs int
+
+	histograms map[string]*Histogram
+	names      []string
+
+	hl *sync.Mutex
+}
+
+func NewHistogramSet(lowest, highest time.Duration, sigfigs int) (*HistogramSet, error) {
+	if _, err := NewHistogram(int64(lowest), int64(highest), sigfigs); err != nil {
+		return nil, err
+	}
+
+	return &HistogramSet{
+		lowest:  int64(lowest),
+		highest: int64(highest),
+		sigfigs: sigfigs,
+
+		histograms: make(map[string]*Histogram),
+		names:      make([]string, 0),
+
+		hl: &sync.Mutex{},
+	}, nil
+}
+
+//Record adds every split of r to the histogram for its name. Out of range
+//splits are skipped and the first such error returned after the rest of the
+//report has been recorded.
+func (s *HistogramSet) Record(r Report) error {
+	var err error
+	for _, split := range r.Splits {
+		h, histErr := s.histogram(split.Name)
+		if histErr == nil {
+			histErr = h.RecordDuration(split.Duration)
+		}
+		if histErr != nil && err == nil {
+			err = histErr
+		}
+	}
+	return err
+}
+
+//Merge folds every histogram of other into the one of the same name.
+func (s *HistogramSet) Merge(other *HistogramSet) error {
+	var err error
+	for _, name := range other.Names() {
+		theirs, _ := other.Histogram(name)
+		ours, histErr := s.histogram(name)
+		if histErr == nil {
+			histErr = ours.Merge(theirs)
+		}
+		if histErr != nil && err == nil {
+			err = histErr
+		}
+	}
+	return err
+}
+
+func (s *HistogramSet) Histogram(name string) (*Histogram, bool) {
+	s.hl.Lock()
+	defer s.hl.Unlock()
+
+	h, ok := s.histograms[name]
+	return h, ok
+}
+
+//Names lists split names in the order they were first recorded.
+func (s *HistogramSet) Names() []string {
+	s.hl.Lock()
+	defer s.hl.Unlock()
+
+	names := make([]string, len(s.names))
+	copy(names, s.names)
+	return names
+}
+
+type histogramSetHeader struct {
+	Lowest  int64
+	Highest int64
+	Sigfigs uint32
+}
+
+//MarshalBinary writes the shared range and precision and then each name with
+//its encoded histogram, both length-prefixed.
+func (s *HistogramSet) MarshalBinary() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	header := histogramSetHeader{Lowest: s.lowest, Highest: s.highest, Sigfigs: uint32(s.sigfigs)}
+	if err := binary.Write(buf, binary.BigEndian, header); err != nil {
+		return nil, err
+	}
+
+	names := s.Names()
+	scratch := make([]byte, binary.MaxVarintLen64)
+	buf.Write(scratch[:binary.PutUvarint(scratch, uint64(len(names)))])
+	for _, name := range names {
+		h, _ := s.Histogram(name)
+		data, err := h.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(scratch[:binary.PutUvarint(scratch, uint64(len(name)))])
+		buf.WriteString(name)
+		buf.Write(scratch[:binary.PutUvarint(scratch, uint64(len(data)))])
+		buf.Write(data)
+	}
+	return buf.Bytes(), nil
+}
+
+//UnmarshalBinary replaces s entirely; every histogram must share the set's
+//range and precision.
+func (s *HistogramSet) UnmarshalBinary(data []byte) error {
+	r := bytes.NewReader(data)
+	header := histogramSetHeader{}
+	if err := binary.Read(r, binary.BigEndian, &header); err != nil {
+		return NewCorruptDataErr("short histogram set header")
+	}
+	count, err := binary.ReadUvarint(r)
+	if err != nil {
+		return NewCorruptDataErr("short histogram set header")
+	}
+
+	decoded, err := NewHistogramSet(time.Duration(header.Lowest), time.Duration(header.Highest), int(header.Sigfigs))
+	if err != nil {
+		return err
+	}
+	for n := uint64(0); n < count; n++ {
+		name, err := readPrefixed(r, "truncated histogram set entry")
+		if err != nil {
+			return err
+		}
+		encoded, err := readPrefixed(r, "truncated histogram set entry")
+		if err != nil {
+			return err
+		}
+		h := &Histogram{}
+		if err := h.UnmarshalBinary(encoded); err != nil {
+			return err
+		}
+		if h.lowest != decoded.lowest || h.highest != decoded.highest || h.sigfigs != decoded.sigfigs {
+			return NewCorruptDataErr(fmt.Sprintf("histogram %q does not match the set's range", name))
+		}
+		decoded.histograms[string(name)] = h
+		decoded.names = append(decoded.names, string(name))
+	}
+
+	if s.hl == nil {
+		s.hl = &sync.Mutex{}
+	}
+	s.hl.Lock()
+	defer s.hl.Unlock()
+
+	hl := s.hl
+	*s = *decoded
+	s.hl = hl
+	return nil
+}
+
+func (s *HistogramSet) histogram(name string) (*Histogram, error) {
+	s.hl.Lock()
+	defer s.hl.Unlock()
+
+	if h, ok := s.histograms[name]; ok {
+		return h, nil
+	}
+	h, err := NewHistogram(s.lowest, s.highest, s.sigfigs)
+	if err != nil {
+		return nil, err
+	}
+	s.histograms[name] = h
+	s.names = append(s.names, name)
+	return h, nil
+}
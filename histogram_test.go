@@ -0,0 +1,337 @@
+package stopwatch
+
+import (
+	"encoding/binary"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"math"
+	"sync"
+	"testing"
+	"time"
+)
+
+//Success
+// - Quantiles within the significant figures
+// - Small values are exact
+// - Values below lowest, including 0
+// - Merge
+// - Binary round trip
+// - Reset
+// - Concurrent record and merge
+// - Set records fixture splits by name
+// - Set merge
+// - Set binary round trip
+//Error
+// - Invalid configuration
+// - Value out of range
+// - Merge drops out of range buckets
+// - Corrupt encoding, including counts that disagree with the header
+// - Set records out of range split
+// - Corrupt set encoding
+func TestHistogram(t *testing.T) {
+	hs := new(histogramSuite)
+	suite.Run(t, hs)
+}
+
+type histogramSuite struct {
+	h       *Histogram
+	set     *HistogramSet
+	reports []Report
+	suite.Suite
+}
+
+func (hs *histogramSuite) SetupTest() {
+	hs.h, _ = NewHistogram(1, int64(time.Hour), 3)
+	hs.set, _ = NewHistogramSet(time.Microsecond, time.Minute, 3)
+	hs.reports = fixtureReports(4)
+}
+
+func (hs *histogramSuite) TestQuantile_Success() {
+	for i := 1; i <= 10000; i++ {
+		assert.Nil(hs.T(), hs.h.RecordDuration(time.Duration(i)*time.Microsecond))
+	}
+
+	assert.Equal(hs.T(), int64(10000), hs.h.TotalCount())
+	assert.Equal(hs.T(), int64(time.Microsecond), hs.h.Min())
+	assert.Equal(hs.T(), int64(10*time.Millisecond), hs.h.Max())
+	assert.InDelta(hs.T(), float64(5000500*time.Nanosecond), hs.h.Mean(), 1)
+
+	expected := map[float64]time.Duration{
+		0.5:   5 * time.Millisecond,
+		0.9:   9 * time.Millisecond,
+		0.99:  9900 * time.Microsecond,
+		0.999: 9990 * time.Microsecond,
+	}
+	for q, want := range expected {
+		got := hs.h.QuantileDuration(q)
+		assert.InEpsilon(hs.T(), float64(want), float64(got), 0.001, "quantile %v", q)
+		assert.True(hs.T(), got >= want, "quantile %v reports the bucket's highest equivalent value", q)
+	}
+	assert.Equal(hs.T(), int64(time.Microsecond), hs.h.Quantile(0))
+	assert.Equal(hs.T(), int64(10*time.Millisecond), hs.h.Quantile(1))
+}
+
+func (hs *histogramSuite) TestQuantile_Success_SmallValuesAreExact() {
+	for v := int64(1); v <= 2000; v++ {
+		hs.h.RecordValue(v)
+	}
+	assert.Equal(hs.T(), int64(1000), hs.h.Quantile(0.5))
+	assert.Equal(hs.T(), int64(1), hs.h.Quantile(0))
+}
+
+func (hs *histogramSuite) TestRecordValue_Success_BelowLowest() {
+	h, _ := NewHistogram(1000, 1000000, 3)
+	assert.Nil(hs.T(), h.RecordValue(0))
+	assert.Nil(hs.T(), h.RecordValue(500))
+	assert.Nil(hs.T(), h.RecordValue(5000))
+
+	assert.Equal(hs.T(), int64(3), h.TotalCount())
+	assert.Zero(hs.T(), h.Min())
+	assert.True(hs.T(), h.Quantile(0.5) < 1000, "values below lowest share the first bucket")
+	assert.InDelta(hs.T(), 5500.0/3, h.Mean(), 1e-9)
+}
+
+func (hs *histogramSuite) TestMerge_Success() {
+	other, _ := NewHistogram(1, int64(time.Hour), 3)
+	for v := int64(1); v <= 500; v++ {
+		hs.h.RecordValue(v)
+		other.RecordValue(v + 500)
+	}
+
+	assert.Nil(hs.T(), hs.h.Merge(other))
+	assert.Equal(hs.T(), int64(1000), hs.h.TotalCount())
+	assert.Equal(hs.T(), int64(1), hs.h.Min())
+	assert.Equal(hs.T(), int64(1000), hs.h.Max())
+	assert.Equal(hs.T(), int64(500), hs.h.Quantile(0.5))
+	assert.InDelta(hs.T(), 500.5, hs.h.Mean(), 1e-9)
+	assert.Equal(hs.T(), int64(500), other.TotalCount())
+}
+
+func (hs *histogramSuite) TestBinaryRoundTrip_Success() {
+	h, _ := NewHistogram(1000, int64(time.Minute), 2)
+	for i := 1; i <= 300; i++ {
+		h.RecordDuration(time.Duration(i*i) * time.Microsecond)
+	}
+
+	data, err := h.MarshalBinary()
+	assert.Nil(hs.T(), err)
+
+	decoded := &Histogram{}
+	assert.Nil(hs.T(), decoded.UnmarshalBinary(data))
+	assert.Equal(hs.T(), h.TotalCount(), decoded.TotalCount())
+	assert.Equal(hs.T(), h.Min(), decoded.Min())
+	assert.Equal(hs.T(), h.Max(), decoded.Max())
+	assert.Equal(hs.T(), h.Mean(), decoded.Mean())
+	for _, q := range []float64{0, 0.25, 0.5, 0.9, 0.99, 1} {
+		assert.Equal(hs.T(), h.Quantile(q), decoded.Quantile(q))
+	}
+
+	//the decoded histogram keeps recording with the original configuration
+	assert.NotNil(hs.T(), decoded.RecordDuration(2*time.Minute))
+}
+
+func (hs *histogramSuite) TestReset_Success() {
+	hs.h.RecordValue(10)
+	hs.h.Reset()
+	assert.Zero(hs.T(), hs.h.TotalCount())
+	assert.Zero(hs.T(), hs.h.Min())
+	assert.Zero(hs.T(), hs.h.Max())
+	hs.h.RecordValue(20)
+	assert.Equal(hs.T(), int64(20), hs.h.Min())
+}
+
+func (hs *histogramSuite) TestConcurrency_Success() {
+	h, _ := NewHistogram(1, math.MaxInt64/4, 3)
+	other, _ := NewHistogram(1, 1000, 3)
+	other.RecordValue(1)
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 1; j <= 100; j++ {
+				h.RecordValue(int64(j))
+				h.Quantile(0.5)
+			}
+			h.Merge(other)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(hs.T(), int64(808), h.TotalCount())
+}
+
+func (hs *histogramSuite) TestHistogramSet_Success() {
+	for _, rpt := range hs.reports {
+		assert.Nil(hs.T(), hs.set.Record(rpt))
+	}
+
+	assert.Equal(hs.T(), []string{"start", "db", "render"}, hs.set.Names())
+	db, ok := hs.set.Histogram("db")
+	assert.True(hs.T(), ok)
+	assert.Equal(hs.T(), int64(4), db.TotalCount())
+	assert.Equal(hs.T(), int64(7*time.Millisecond), db.Min())
+	assert.Equal(hs.T(), int64(10*time.Millisecond), db.Max())
+	assert.InEpsilon(hs.T(), float64(8*time.Millisecond), float64(db.QuantileDuration(0.5)), 0.001)
+
+	_, ok = hs.set.Histogram("missing")
+	assert.False(hs.T(), ok)
+}
+
+func (hs *histogramSuite) TestHistogramSet_Success_Merge() {
+	other, _ := NewHistogramSet(time.Microsecond, time.Minute, 3)
+	hs.set.Record(hs.reports[0])
+	extra := hs.reports[1]
+	extra.Splits = append(extra.Splits, Split{Name: "flush", Duration: time.Second})
+	other.Record(extra)
+
+	assert.Nil(hs.T(), hs.set.Merge(other))
+	assert.Equal(hs.T(), []string{"start", "db", "render", "flush"}, hs.set.Names())
+	db, _ := hs.set.Histogram("db")
+	assert.Equal(hs.T(), int64(2), db.TotalCount())
+}
+
+func (hs *histogramSuite) TestHistogramSet_Success_BinaryRoundTrip() {
+	for _, rpt := range hs.reports {
+		hs.set.Record(rpt)
+	}
+
+	data, err := hs.set.MarshalBinary()
+	assert.Nil(hs.T(), err)
+	received := &HistogramSet{}
+	assert.Nil(hs.T(), received.UnmarshalBinary(data))
+	assert.Equal(hs.T(), []string{"start", "db", "render"}, received.Names())
+
+	merged, _ := NewHistogramSet(time.Microsecond, time.Minute, 3)
+	merged.Record(hs.reports[0])
+	assert.Nil(hs.T(), merged.Merge(received))
+	db, _ := merged.Histogram("db")
+	assert.Equal(hs.T(), int64(5), db.TotalCount())
+	assert.Equal(hs.T(), int64(7*time.Millisecond), db.Min())
+	assert.Equal(hs.T(), int64(10*time.Millisecond), db.Max())
+
+	//the decoded set keeps recording with the original configuration
+	assert.NotNil(hs.T(), received.Record(Report{Splits: []Split{{Name: "db", Duration: time.Hour}}}))
+}
+
+func (hs *histogramSuite) TestNewHistogram_Error() {
+	_, err := NewHistogram(0, 100, 3)
+	assert.Equal(hs.T(), NewInvalidHistogramErr("lowest trackable value must be at least 1").Error(), err.Error())
+
+	_, err = NewHistogram(10, 15, 3)
+	assert.NotNil(hs.T(), err)
+
+	_, err = NewHistogram(1, 100, 6)
+	assert.Equal(hs.T(), NewInvalidHistogramErr("significant figures must be between 1 and 5").Error(), err.Error())
+
+	_, err = NewHistogram(1<<62, math.MaxInt64, 1)
+	assert.Equal(hs.T(), NewInvalidHistogramErr("lowest trackable value is too large for the significant figures").Error(), err.Error())
+
+	_, err = NewHistogramSet(0, time.Second, 3)
+	assert.NotNil(hs.T(), err)
+}
+
+func (hs *histogramSuite) TestRecordValue_Error_OutOfRange() {
+	h, _ := NewHistogram(10, 1000, 2)
+	err := h.RecordValue(5000)
+	assert.Equal(hs.T(), NewValueOutOfRangeErr(5000, 0, 1000).Error(), err.Error())
+	err = h.RecordValue(-1)
+	assert.Equal(hs.T(), NewValueOutOfRangeErr(-1, 0, 1000).Error(), err.Error())
+	assert.Zero(hs.T(), h.TotalCount())
+	assert.Zero(hs.T(), h.Quantile(0.5))
+}
+
+func (hs *histogramSuite) TestMerge_Error_OutOfRange() {
+	narrow, _ := NewHistogram(1, 100, 3)
+	hs.h.RecordValue(50)
+	hs.h.RecordValue(50000)
+
+	err := narrow.Merge(hs.h)
+	assert.NotNil(hs.T(), err)
+	assert.Equal(hs.T(), int64(1), narrow.TotalCount())
+	assert.Equal(hs.T(), int64(50), narrow.Max())
+}
+
+func (hs *histogramSuite) TestUnmarshalBinary_Error() {
+	h := &Histogram{}
+	err := h.UnmarshalBinary([]byte{0, 0})
+	assert.Equal(hs.T(), NewCorruptDataErr("short histogram header").Error(), err.Error())
+
+	good, _ := NewHistogram(1, 1000, 3)
+	good.RecordValue(10)
+	data, _ := good.MarshalBinary()
+	err = h.UnmarshalBinary(data[:len(data)-1])
+	assert.Equal(hs.T(), NewCorruptDataErr("truncated bucket").Error(), err.Error())
+
+	data[3] = 9
+	err = h.UnmarshalBinary(data)
+	assert.Equal(hs.T(), NewCorruptDataErr("unsupported histogram encoding version 9").Error(), err.Error())
+
+	//a header whose range cannot be allocated is rejected rather than hanging
+	data[3] = histogramEncodingVersion
+	binary.BigEndian.PutUint64(data[4:], 1<<62)
+	binary.BigEndian.PutUint64(data[12:], math.MaxInt64)
+	err = h.UnmarshalBinary(data)
+	assert.Equal(hs.T(), NewInvalidHistogramErr("lowest trackable value is too large for the significant figures").Error(), err.Error())
+
+	//header fields and buckets that disagree are rejected rather than decoded
+	data, _ = good.MarshalBinary()
+	header := data[:60]
+	scratch := make([]byte, binary.MaxVarintLen64)
+	overflow := append([]byte(nil), header...)
+	overflow = append(overflow, scratch[:binary.PutUvarint(scratch, 1)]...)
+	overflow = append(overflow, scratch[:binary.PutUvarint(scratch, 1<<63+5)]...)
+	err = h.UnmarshalBinary(overflow)
+	assert.Equal(hs.T(), NewCorruptDataErr("bucket 1 count overflows").Error(), err.Error())
+
+	repeated := append([]byte(nil), data...)
+	binary.BigEndian.PutUint32(repeated[56:], 2)
+	binary.BigEndian.PutUint64(repeated[24:], 2)
+	repeated = append(repeated, data[60:]...)
+	err = h.UnmarshalBinary(repeated)
+	assert.Equal(hs.T(), NewCorruptDataErr(fmt.Sprintf("bucket %d repeated", good.countsIndexFor(10))).Error(), err.Error())
+
+	corrupt := append([]byte(nil), data...)
+	binary.BigEndian.PutUint64(corrupt[24:], 2)
+	err = h.UnmarshalBinary(corrupt)
+	assert.Equal(hs.T(), NewCorruptDataErr("bucket counts sum to 1, header says 2").Error(), err.Error())
+
+	corrupt = append([]byte(nil), data...)
+	binary.BigEndian.PutUint64(corrupt[32:], uint64(1<<64-1))
+	err = h.UnmarshalBinary(corrupt)
+	assert.Equal(hs.T(), NewCorruptDataErr("min -1 and max 10 outside [0, 1000]").Error(), err.Error())
+
+	corrupt = append([]byte(nil), data...)
+	binary.BigEndian.PutUint64(corrupt[40:], 5000)
+	err = h.UnmarshalBinary(corrupt)
+	assert.Equal(hs.T(), NewCorruptDataErr("min 10 and max 5000 outside [0, 1000]").Error(), err.Error())
+}
+
+func (hs *histogramSuite) TestHistogramSet_Error_RecordOutOfRange() {
+	set, _ := NewHistogramSet(time.Microsecond, time.Second, 3)
+	rpt := hs.reports[0]
+	rpt.Splits = append([]Split{{Name: "slow", Duration: time.Hour}}, rpt.Splits...)
+	err := set.Record(rpt)
+	assert.NotNil(hs.T(), err)
+
+	db, ok := set.Histogram("db")
+	assert.True(hs.T(), ok)
+	assert.Equal(hs.T(), int64(1), db.TotalCount())
+}
+
+func (hs *histogramSuite) TestHistogramSet_Error_UnmarshalBinary() {
+	hs.set.Record(hs.reports[0])
+	data, _ := hs.set.MarshalBinary()
+
+	err := (&HistogramSet{}).UnmarshalBinary(data[:24])
+	assert.Equal(hs.T(), NewCorruptDataErr("truncated histogram set entry").Error(), err.Error())
+	err = (&HistogramSet{}).UnmarshalBinary(data[:4])
+	assert.Equal(hs.T(), NewCorruptDataErr("short histogram set header").Error(), err.Error())
+
+	mismatched, _ := NewHistogramSet(time.Microsecond, time.Minute, 3)
+	mismatched.histograms["db"], _ = NewHistogram(1, 1000, 3)
+	mismatched.names = append(mismatched.names, "db")
+	data, _ = mismatched.MarshalBinary()
+	err = (&HistogramSet{}).UnmarshalBinary(data)
+	assert.Equal(hs.T(), NewCorruptDataErr(`histogram "db" does not match the set's range`).Error(), err.Error())
+}
@@ -0,0 +1,339 @@
+package stopwatch
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"time"
+)
+
+const (
+	htmlTimelineWidth = 800.0
+	htmlLabelWidth    = 160.0
+	htmlRowHeight     = 18.0
+	htmlHistWidth     = 200.0
+	htmlHistHeight    = 40.0
+	htmlHistBins      = 10
+)
+
+type HTMLOptions struct {
+	Title string
+}
+
+type htmlPage struct {
+	Title         string
+	HistWidth     float64
+	HistHeight    float64
+	Rows          []htmlRow
+	Timelines     []htmlTimeline
+	Distributions []htmlDistribution
+}
+
+type htmlRow struct {
+	Run        int
+	Stopwatch  string
+	Split      string
+	Comment    string
+	OffsetNs   int64
+	Offset     string
+	DurationNs int64
+	Duration   string
+}
+
+type htmlTimeline struct {
+	Title  string
+	Width  float64
+	Height float64
+	Bars   []htmlBar
+}
+
+type htmlBar struct {
+	Label string
+	Title string
+	X     float64
+	Y     float64
+	W     float64
+}
+
+type htmlDistribution struct {
+	Stopwatch string
+	Split     string
+	Count     int
+	Min       string
+	P50       string
+	P90       string
+	Max       string
+	Mean      string
+	Bins      []htmlBin
+}
+
+type htmlBin struct {
+	Title string
+	X     float64
+	Y     float64
+	W     float64
+	H     float64
+}
+
+type htmlRun struct {
+	run int
+	rpt Report
+}
+
+var htmlReport = template.Must(template.New("report").Parse(htmlTemplate))
+
+//WriteHTML renders reports (and their children) into a single page with no
+//external assets: a sortable split table, a timeline per run and a duration
+//histogram per stopwatch/split pair.
+func WriteHTML(w io.Writer, opts HTMLOptions, reports ...Report) error {
+	title := opts.Title
+	if title == "" {
+		title = "Stopwatch report"
+	}
+
+	runs := make([]htmlRun, 0, len(reports))
+	for i, rpt := range reports {
+		runs = flattenRun(runs, i, rpt)
+	}
+
+	page := htmlPage{
+		Title:         title,
+		HistWidth:     htmlHistWidth,
+		HistHeight:    htmlHistHeight,
+		Rows:          make([]htmlRow, 0),
+		Timelines:     make([]htmlTimeline, 0, len(runs)),
+		Distributions: htmlDistributions(runs),
+	}
+	for _, run := range runs {
+		page.Rows = append(page.Rows, htmlRows(run)...)
+		page.Timelines = append(page.Timelines, htmlTimelineFor(run))
+	}
+
+	return htmlReport.Execute(w, page)
+}
+
+func flattenRun(runs []htmlRun, run int, rpt Report) []htmlRun {
+	runs = append(runs, htmlRun{run: run, rpt: rpt})
+	for _, child := range rpt.Children {
+		runs = flattenRun(runs, run, child)
+	}
+
+	return runs
+}
+
+func htmlRows(run htmlRun) []htmlRow {
+	spans, _ := ganttSpans(run.rpt)
+	rows := make([]htmlRow, len(run.rpt.Splits))
+	for i, split := range run.rpt.Splits {
+		rows[i] = htmlRow{
+			Run:        run.run,
+			Stopwatch:  run.rpt.Name,
+			Split:      split.Name,
+			Comment:    split.Comment,
+			OffsetNs:   int64(spans[i].from),
+			Offset:     spans[i].from.String(),
+			DurationNs: int64(split.Duration),
+			Duration:   split.Duration.String(),
+		}
+	}
+
+	return rows
+}
+
+func htmlTimelineFor(run htmlRun) htmlTimeline {
+	spans, total := ganttSpans(run.rpt)
+	scale := 0.0
+	if total > 0 {
+		scale = (htmlTimelineWidth - htmlLabelWidth) / float64(total)
+	}
+
+	bars := make([]htmlBar, len(run.rpt.Splits))
+	for i, split := range run.rpt.Splits {
+		width := float64(spans[i].to-spans[i].from) * scale
+		if width < 1 {
+			width = 1
+		}
+		bars[i] = htmlBar{
+			Label: split.Name,
+			Title: fmt.Sprintf("%s: %s %s", split.Name, split.Duration, split.Comment),
+			X:     htmlLabelWidth + float64(spans[i].from)*scale,
+			Y:     float64(i) * htmlRowHeight,
+			W:     width,
+		}
+	}
+
+	return htmlTimeline{
+		Title:  fmt.Sprintf("run %d: %s (%s)", run.run, run.rpt.Name, run.rpt.Duration),
+		Width:  htmlTimelineWidth,
+		Height: float64(len(bars)) * htmlRowHeight,
+		Bars:   bars,
+	}
+}
+
+func htmlDistributions(runs []htmlRun) []htmlDistribution {
+	type pair struct {
+		stopwatch string
+		split     string
+	}
+
+	order := make([]pair, 0)
+	samples := make(map[pair][]time.Duration)
+	for _, run := range runs {
+		for _, split := range run.rpt.Splits {
+			p := pair{stopwatch: run.rpt.Name, split: split.Name}
+			if _, seen := samples[p]; !seen {
+				order = append(order, p)
+			}
+			samples[p] = append(samples[p], split.Duration)
+		}
+	}
+
+	distributions := make([]htmlDistribution, len(order))
+	for i, p := range order {
+		durations := samples[p]
+		sort.Slice(durations, func(i, j int) bool {
+			return durations[i] < durations[j]
+		})
+
+		var total time.Duration
+		for _, d := range durations {
+			total += d
+		}
+
+		distributions[i] = htmlDistribution{
+			Stopwatch: p.stopwatch,
+			Split:     p.split,
+			Count:     len(durations),
+			Min:       durations[0].String(),
+			P50:       percentile(durations, 0.5).String(),
+			P90:       percentile(durations, 0.9).String(),
+			Max:       durations[len(durations)-1].String(),
+			Mean:      (total / time.Duration(len(durations))).String(),
+			Bins:      htmlHistogram(durations),
+		}
+	}
+
+	return distributions
+}
+
+func htmlHistogram(sorted []time.Duration) []htmlBin {
+	lo, hi := sorted[0], sorted[len(sorted)-1]
+	width := (hi - lo) / htmlHistBins
+	if width <= 0 {
+		width = 1
+	}
+
+	counts := make([]int, htmlHistBins)
+	tallest := 0
+	for _, d := range sorted {
+		bin := int((d - lo) / width)
+		if bin >= htmlHistBins {
+			bin = htmlHistBins - 1
+		}
+		counts[bin]++
+		if counts[bin] > tallest {
+			tallest = counts[bin]
+		}
+	}
+
+	binWidth := htmlHistWidth / htmlHistBins
+	bins := make([]htmlBin, htmlHistBins)
+	for i, count := range counts {
+		h := htmlHistHeight * float64(count) / float64(tallest)
+		from := lo + time.Duration(i)*width
+		bins[i] = htmlBin{
+			Title: fmt.Sprintf("%s - %s: %d", from, from+width, count),
+			X:     float64(i) * binWidth,
+			Y:     htmlHistHeight - h,
+			W:     binWidth - 1,
+			H:     h,
+		}
+	}
+
+	return bins
+}
+
+const htmlTemplate = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { padding: 2px 10px; border-bottom: 1px solid #ddd; text-align: left; }
+th { cursor: pointer; background: #f4f4f4; user-select: none; }
+td.num { text-align: right; font-variant-numeric: tabular-nums; }
+svg text { font-size: 11px; }
+rect.split { fill: #4a90d9; }
+rect.bin { fill: #7cb46b; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+
+<h2>Splits</h2>
+<table id="splits">
+<thead>
+<tr><th data-type="num">Run</th><th>Stopwatch</th><th>Split</th><th>Comment</th><th data-type="num">Offset</th><th data-type="num">Duration</th></tr>
+</thead>
+<tbody>
+{{- range .Rows}}
+<tr><td class="num" data-sort="{{.Run}}">{{.Run}}</td><td>{{.Stopwatch}}</td><td>{{.Split}}</td><td>{{.Comment}}</td><td class="num" data-sort="{{.OffsetNs}}">{{.Offset}}</td><td class="num" data-sort="{{.DurationNs}}">{{.Duration}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+
+<h2>Timelines</h2>
+{{- range .Timelines}}
+<h3>{{.Title}}</h3>
+<svg width="{{.Width}}" height="{{.Height}}">
+{{- range .Bars}}
+<text x="0" y="{{.Y}}" dy="13">{{.Label}}</text>
+<rect class="split" x="{{.X}}" y="{{.Y}}" width="{{.W}}" height="14"><title>{{.Title}}</title></rect>
+{{- end}}
+</svg>
+{{- end}}
+
+<h2>Distributions</h2>
+<table>
+<thead>
+<tr><th>Stopwatch</th><th>Split</th><th>Count</th><th>Min</th><th>p50</th><th>p90</th><th>Max</th><th>Mean</th><th>Histogram</th></tr>
+</thead>
+<tbody>
+{{- range .Distributions}}
+<tr><td>{{.Stopwatch}}</td><td>{{.Split}}</td><td class="num">{{.Count}}</td><td class="num">{{.Min}}</td><td class="num">{{.P50}}</td><td class="num">{{.P90}}</td><td class="num">{{.Max}}</td><td class="num">{{.Mean}}</td>
+<td><svg width="{{$.HistWidth}}" height="{{$.HistHeight}}">{{range .Bins}}<rect class="bin" x="{{.X}}" y="{{.Y}}" width="{{.W}}" height="{{.H}}"><title>{{.Title}}</title></rect>{{end}}</svg></td></tr>
+{{- end}}
+</tbody>
+</table>
+
+<script>
+(function () {
+  var table = document.getElementById("splits");
+  var headers = table.tHead.rows[0].cells;
+  for (var i = 0; i < headers.length; i++) {
+    headers[i].addEventListener("click", sortBy(i, headers[i].getAttribute("data-type") === "num"));
+  }
+  function sortBy(col, numeric) {
+    var ascending = true;
+    return function () {
+      var body = table.tBodies[0];
+      var rows = Array.prototype.slice.call(body.rows);
+      rows.sort(function (a, b) {
+        var x = a.cells[col], y = b.cells[col];
+        var cmp = numeric
+          ? Number(x.getAttribute("data-sort")) - Number(y.getAttribute("data-sort"))
+          : x.textContent.localeCompare(y.textContent);
+        return ascending ? cmp : -cmp;
+      });
+      ascending = !ascending;
+      rows.forEach(function (row) { body.appendChild(row); });
+    };
+  }
+})();
+</script>
+</body>
+</html>
+`
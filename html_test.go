@@ -0,0 +1,91 @@
+package stopwatch
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"strings"
+	"testing"
+)
+
+//Success
+// - Tables, timelines and histograms
+// - Comments are escaped
+// - Distributions
+// - Rendered percentiles round the rank up
+// - Default title
+func TestWriteHTML(t *testing.T) {
+	hs := new(htmlSuite)
+	suite.Run(t, hs)
+}
+
+type htmlSuite struct {
+	reports []Report
+	buf     *bytes.Buffer
+	suite.Suite
+}
+
+func (hs *htmlSuite) SetupTest() {
+	hs.reports = fixtureReports(4)
+	hs.buf = &bytes.Buffer{}
+}
+
+func (hs *htmlSuite) TestWriteHTML_Success() {
+	err := WriteHTML(hs.buf, HTMLOptions{Title: "CI <timings>"}, hs.reports...)
+	assert.Nil(hs.T(), err)
+	out := hs.buf.String()
+
+	assert.True(hs.T(), strings.HasPrefix(out, "<!DOCTYPE html>"))
+	assert.Contains(hs.T(), out, "<title>CI &lt;timings&gt;</title>")
+	assert.NotContains(hs.T(), out, "http://")
+	assert.NotContains(hs.T(), out, "https://")
+
+	//4 runs with 3 handler splits and 2 db splits each
+	assert.Equal(hs.T(), 20, strings.Count(out, `<tr><td class="num" data-sort=`))
+	assert.Equal(hs.T(), 20, strings.Count(out, `<rect class="split"`))
+	assert.Equal(hs.T(), 8, strings.Count(out, "<h3>"))
+	assert.Contains(hs.T(), out, "<h3>run 3: db (11ms)</h3>")
+	assert.Equal(hs.T(), 5*htmlHistBins, strings.Count(out, `<rect class="bin"`))
+}
+
+func (hs *htmlSuite) TestWriteHTML_Success_EscapesComments() {
+	hs.reports[0].Splits[1].Comment = "<b>html</b>"
+	err := WriteHTML(hs.buf, HTMLOptions{}, hs.reports...)
+	assert.Nil(hs.T(), err)
+	assert.NotContains(hs.T(), hs.buf.String(), "<b>html</b>")
+	assert.Contains(hs.T(), hs.buf.String(), "&lt;b&gt;html&lt;/b&gt;")
+}
+
+func (hs *htmlSuite) TestWriteHTML_Success_Distributions() {
+	runs := make([]htmlRun, 0)
+	for i, rpt := range hs.reports {
+		runs = flattenRun(runs, i, rpt)
+	}
+
+	distributions := htmlDistributions(runs)
+	assert.Equal(hs.T(), 5, len(distributions))
+	db := distributions[1]
+	assert.Equal(hs.T(), "handler", db.Stopwatch)
+	assert.Equal(hs.T(), "db", db.Split)
+	assert.Equal(hs.T(), 4, db.Count)
+	assert.Equal(hs.T(), "7ms", db.Min)
+	assert.Equal(hs.T(), "8ms", db.P50)
+	assert.Equal(hs.T(), "10ms", db.P90)
+	assert.Equal(hs.T(), "10ms", db.Max)
+	assert.Equal(hs.T(), "8.5ms", db.Mean)
+	assert.Equal(hs.T(), "query;select", distributions[4].Split)
+}
+
+func (hs *htmlSuite) TestWriteHTML_Success_RenderedPercentiles() {
+	//with 9 runs the 90th percentile rank is 8.1, which must round up to the 9th
+	err := WriteHTML(hs.buf, HTMLOptions{}, fixtureReports(9)...)
+	assert.Nil(hs.T(), err)
+	assert.Contains(hs.T(), hs.buf.String(),
+		`<tr><td>handler</td><td>db</td><td class="num">9</td><td class="num">7ms</td><td class="num">11ms</td><td class="num">15ms</td><td class="num">15ms</td>`)
+}
+
+func (hs *htmlSuite) TestWriteHTML_Success_DefaultTitle() {
+	err := WriteHTML(hs.buf, HTMLOptions{})
+	assert.Nil(hs.T(), err)
+	assert.Contains(hs.T(), hs.buf.String(), "<title>Stopwatch report</title>")
+}
@@ -0,0 +1,96 @@
+package stopwatch
+
+import "time"
+
+func (w *Stopwatch) Begin(name string) error {
+	return w.stamp(func() (record, error) {
+		if w.stopped() {
+			return record{}, NewAlreadyStoppedErr(w)
+		}
+
+		if !w.started() {
+			return record{}, NewNotStartedErr(w)
+		}
+
+		if err := w.validateKey(name); err != nil {
+			return record{}, err
+		}
+
+		if _, open := w.openInterval(name); open {
+			return record{}, NewIntervalOpenErr(w, name)
+		}
+
+		ts := w.Clock.Now()
+		w.intervals = append(w.intervals, namedInterval{
+			name:     name,
+			interval: interval{begin: ts},
+		})
+		return newRecord(intervalBegin, ts, name), nil
+	})
+}
+
+func (w *Stopwatch) End(name string) error {
+	return w.stamp(func() (record, error) {
+		if w.stopped() {
+			return record{}, NewAlreadyStoppedErr(w)
+		}
+
+		if !w.started() {
+			return record{}, NewNotStartedErr(w)
+		}
+
+		idx, open := w.openInterval(name)
+		if !open {
+			return record{}, NewIntervalNotOpenErr(w, name)
+		}
+
+		ts := w.Clock.Now()
+		w.intervals[idx].end = ts
+		return newRecord(intervalEnd, ts, name), nil
+	})
+}
+
+//Between measures active time from the first fromKey lap to the first toKey
+//lap, regardless of the laps recorded in between.
+func (w *Stopwatch) Between(fromKey, toKey string) (time.Duration, error) {
+	w.rl.Lock()
+	defer w.rl.Unlock()
+
+	if !w.started() {
+		return time.Duration(0), NewNotStartedErr(w)
+	}
+
+	return w.calculateDuration(newKey(fromKey), newKey(toKey))
+}
+
+func (w *Stopwatch) openInterval(name string) (int, bool) {
+	for i := len(w.intervals) - 1; i >= 0; i-- {
+		if w.intervals[i].name == name && w.intervals[i].end.IsZero() {
+			return i, true
+		}
+	}
+
+	return -1, false
+}
+
+func (w *Stopwatch) calculateNamedIntervals(named []namedInterval, now time.Time) []Interval {
+	intervals := make([]Interval, 0, len(named))
+	for _, n := range named {
+		if n.begin.After(now) {
+			continue
+		}
+		until := n.end
+		if until.IsZero() || until.After(now) {
+			until = now
+		}
+
+		intervals = append(intervals, Interval{
+			Name:     n.name,
+			Start:    n.begin,
+			End:      until,
+			Duration: w.durationBetween(n.begin, until),
+		})
+	}
+
+	return intervals
+}
@@ -0,0 +1,186 @@
+package stopwatch
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+//Success
+// - Overlapping and nested intervals
+// - Repeated interval name
+// - Open interval ends at stop
+//Error
+// - NotStarted
+// - AlreadyStopped
+// - InvalidKey
+// - AlreadyOpen
+// - NotOpen
+func TestIntervals(t *testing.T) {
+	is := new(intervalSuite)
+	suite.Run(t, is)
+}
+
+type intervalSuite struct {
+	w      *Stopwatch
+	clock  *ManualClock
+	logger *testLogger
+	suite.Suite
+}
+
+func (is *intervalSuite) SetupTest() {
+	is.logger = &testLogger{}
+	is.clock = NewManualClock(time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC))
+	is.w = New("test", is.logger, is.clock)
+}
+
+func (is *intervalSuite) TestIntervals_Success() {
+	_ = is.w.Start()
+	assert.Nil(is.T(), is.w.Begin("request"))
+	is.clock.Advance(time.Second)
+	assert.Nil(is.T(), is.w.Begin("db"))
+	_ = is.w.Lap("query", "")
+	is.clock.Advance(2 * time.Second)
+	assert.Nil(is.T(), is.w.Begin("render"))
+	is.clock.Advance(time.Second)
+	assert.Nil(is.T(), is.w.End("db"))
+	is.clock.Advance(time.Second)
+	assert.Nil(is.T(), is.w.End("render"))
+	assert.Nil(is.T(), is.w.End("request"))
+	_ = is.w.Stop()
+
+	rpt, err := is.w.Report()
+	assert.Nil(is.T(), err)
+	assert.Equal(is.T(), 2, len(rpt.Splits))
+	expected := map[string]time.Duration{
+		"request": 5 * time.Second,
+		"db":      3 * time.Second,
+		"render":  2 * time.Second,
+	}
+	assert.Equal(is.T(), len(expected), len(rpt.Intervals))
+	for _, i := range rpt.Intervals {
+		assert.Equal(is.T(), expected[i.Name], i.Duration, i.Name)
+	}
+
+	expectedNumOfLogs := 9
+	assert.Equal(is.T(), expectedNumOfLogs, len(is.logger.logs))
+	assert.Equal(is.T(), intervalBegin.String(), is.logger.logs[1].key)
+	assert.Equal(is.T(), "request", is.logger.logs[1].comment)
+}
+
+func (is *intervalSuite) TestIntervals_Success_RepeatedName() {
+	_ = is.w.Start()
+	for i := 1; i <= 2; i++ {
+		_ = is.w.Begin("retry")
+		is.clock.Advance(time.Duration(i) * time.Second)
+		_ = is.w.End("retry")
+	}
+	_ = is.w.Stop()
+
+	rpt, _ := is.w.Report()
+	assert.Equal(is.T(), 2, len(rpt.Intervals))
+	assert.Equal(is.T(), time.Second, rpt.Intervals[0].Duration)
+	assert.Equal(is.T(), 2*time.Second, rpt.Intervals[1].Duration)
+}
+
+func (is *intervalSuite) TestIntervals_Success_OpenAtStop() {
+	_ = is.w.Start()
+	_ = is.w.Begin("request")
+	is.clock.Advance(time.Second)
+	_ = is.w.Stop()
+
+	rpt, _ := is.w.Report()
+	assert.Equal(is.T(), 1, len(rpt.Intervals))
+	assert.Equal(is.T(), time.Second, rpt.Intervals[0].Duration)
+	assert.Equal(is.T(), is.clock.Now(), rpt.Intervals[0].End)
+}
+
+func (is *intervalSuite) TestIntervals_Error_NotStarted() {
+	err := is.w.Begin("request")
+	assert.NotNil(is.T(), err)
+	assert.Equal(is.T(), NewNotStartedErr(is.w).Error(), err.Error())
+
+	err = is.w.End("request")
+	assert.NotNil(is.T(), err)
+	assert.Equal(is.T(), NewNotStartedErr(is.w).Error(), err.Error())
+}
+
+func (is *intervalSuite) TestIntervals_Error_AlreadyStopped() {
+	_ = is.w.Start()
+	_ = is.w.Begin("request")
+	_ = is.w.Stop()
+
+	err := is.w.End("request")
+	assert.NotNil(is.T(), err)
+	assert.Equal(is.T(), NewAlreadyStoppedErr(is.w).Error(), err.Error())
+
+	err = is.w.Begin("db")
+	assert.NotNil(is.T(), err)
+	assert.Equal(is.T(), NewAlreadyStoppedErr(is.w).Error(), err.Error())
+}
+
+func (is *intervalSuite) TestIntervals_Error_InvalidKey() {
+	_ = is.w.Start()
+	err := is.w.Begin("")
+	assert.NotNil(is.T(), err)
+	assert.Equal(is.T(), NewInvalidKeyErr(is.w, "", "key is empty").Error(), err.Error())
+}
+
+func (is *intervalSuite) TestBegin_Error_AlreadyOpen() {
+	_ = is.w.Start()
+	_ = is.w.Begin("request")
+	err := is.w.Begin("request")
+	assert.NotNil(is.T(), err)
+	assert.IsType(is.T(), &StopwatchErr{}, err)
+	assert.Equal(is.T(), NewIntervalOpenErr(is.w, "request").Error(), err.Error())
+}
+
+func (is *intervalSuite) TestEnd_Error_NotOpen() {
+	_ = is.w.Start()
+	err := is.w.End("request")
+	assert.NotNil(is.T(), err)
+	assert.IsType(is.T(), &StopwatchErr{}, err)
+	assert.Equal(is.T(), NewIntervalNotOpenErr(is.w, "request").Error(), err.Error())
+
+	_ = is.w.Begin("request")
+	_ = is.w.End("request")
+	err = is.w.End("request")
+	assert.NotNil(is.T(), err)
+	assert.Equal(is.T(), NewIntervalNotOpenErr(is.w, "request").Error(), err.Error())
+}
+
+//Success
+//Error
+// - NotStarted
+// - NonExistentKey
+func TestBetween(t *testing.T) {
+	clock := NewManualClock(time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC))
+	w := New("test", nil, clock)
+
+	dur, err := w.Between("auth", "response")
+	assert.Zero(t, dur)
+	assert.NotNil(t, err)
+	assert.Equal(t, NewNotStartedErr(w).Error(), err.Error())
+
+	_ = w.Start()
+	_ = w.Lap("auth", "")
+	clock.Advance(time.Second)
+	_ = w.Lap("db", "")
+	clock.Advance(2 * time.Second)
+	_ = w.Lap("response", "")
+
+	dur, err = w.Between("auth", "response")
+	assert.Nil(t, err)
+	assert.Equal(t, 3*time.Second, dur)
+
+	dur, err = w.Between("start", "db")
+	assert.Nil(t, err)
+	assert.Equal(t, time.Second, dur)
+
+	dur, err = w.Between("auth", "render")
+	assert.Zero(t, dur)
+	assert.NotNil(t, err)
+	assert.IsType(t, &StopwatchErr{}, err)
+	assert.Equal(t, NewNonExistentKeyErr(w, newKey("render")).Error(), err.Error())
+}
@@ -0,0 +1,139 @@
+package stopwatch
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const ReportSchemaVersion = 1
+
+type jsonReport struct {
+	Version    int        `json:"version"`
+	Name       string     `json:"name"`
+	StartedAt  time.Time  `json:"started_at"`
+	StoppedAt  *time.Time `json:"stopped_at,omitempty"`
+	DurationNs int64      `json:"duration_ns"`
+	Duration   string     `json:"duration"`
+	PausedNs   int64      `json:"paused_ns"`
+	Paused     string     `json:"paused"`
+	Splits     []Split    `json:"splits"`
+	Pauses     []Interval `json:"pauses"`
+	Branches   []Interval `json:"branches"`
+	Intervals  []Interval `json:"intervals"`
+	Children   []Report   `json:"children"`
+}
+
+type jsonSplit struct {
+	Name       string    `json:"name"`
+	Comment    string    `json:"comment"`
+	Start      time.Time `json:"start"`
+	End        time.Time `json:"end"`
+	DurationNs int64     `json:"duration_ns"`
+	Duration   string    `json:"duration"`
+}
+
+type jsonInterval struct {
+	Name       string    `json:"name"`
+	Start      time.Time `json:"start"`
+	End        time.Time `json:"end"`
+	DurationNs int64     `json:"duration_ns"`
+	Duration   string    `json:"duration"`
+}
+
+func (r Report) MarshalJSON() ([]byte, error) {
+	jr := jsonReport{
+		Version:    ReportSchemaVersion,
+		Name:       r.Name,
+		StartedAt:  r.StartedAt,
+		DurationNs: int64(r.Duration),
+		Duration:   r.Duration.String(),
+		PausedNs:   int64(r.PausedDuration),
+		Paused:     r.PausedDuration.String(),
+		Splits:     r.Splits,
+		Pauses:     r.Pauses,
+		Branches:   r.Branches,
+		Intervals:  r.Intervals,
+		Children:   r.Children,
+	}
+	if !r.StoppedAt.IsZero() {
+		jr.StoppedAt = &r.StoppedAt
+	}
+
+	return json.Marshal(jr)
+}
+
+//UnmarshalJSON trusts the nanosecond fields; the human readable durations
+//are only written for people reading the payload.
+func (r *Report) UnmarshalJSON(data []byte) error {
+	var jr jsonReport
+	if err := json.Unmarshal(data, &jr); err != nil {
+		return err
+	}
+
+	if jr.Version != ReportSchemaVersion {
+		return NewUnsupportedVersionErr(jr.Version)
+	}
+
+	*r = Report{
+		Name:           jr.Name,
+		StartedAt:      jr.StartedAt,
+		Duration:       time.Duration(jr.DurationNs),
+		PausedDuration: time.Duration(jr.PausedNs),
+		Splits:         jr.Splits,
+		Pauses:         jr.Pauses,
+		Branches:       jr.Branches,
+		Intervals:      jr.Intervals,
+		Children:       jr.Children,
+	}
+	if jr.StoppedAt != nil {
+		r.StoppedAt = *jr.StoppedAt
+	}
+
+	return nil
+}
+
+func (s Split) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonSplit{
+		Name:       s.Name,
+		Comment:    s.Comment,
+		Start:      s.Start,
+		End:        s.End,
+		DurationNs: int64(s.Duration),
+		Duration:   s.Duration.String(),
+	})
+}
+
+func (s *Split) UnmarshalJSON(data []byte) error {
+	var js jsonSplit
+	if err := json.Unmarshal(data, &js); err != nil {
+		return err
+	}
+
+	*s = newSplit(js.Name, js.Comment, js.Start, js.End, time.Duration(js.DurationNs))
+	return nil
+}
+
+func (i Interval) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonInterval{
+		Name:       i.Name,
+		Start:      i.Start,
+		End:        i.End,
+		DurationNs: int64(i.Duration),
+		Duration:   i.Duration.String(),
+	})
+}
+
+func (i *Interval) UnmarshalJSON(data []byte) error {
+	var ji jsonInterval
+	if err := json.Unmarshal(data, &ji); err != nil {
+		return err
+	}
+
+	*i = Interval{
+		Name:     ji.Name,
+		Start:    ji.Start,
+		End:      ji.End,
+		Duration: time.Duration(ji.DurationNs),
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package stopwatch
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+	"time"
+)
+
+//Success
+// - Round trip
+// - Round trip snapshot
+// - Wire format
+//Error
+// - UnsupportedVersion
+// - Malformed
+func TestReportJSON(t *testing.T) {
+	js := new(jsonSuite)
+	suite.Run(t, js)
+}
+
+type jsonSuite struct {
+	clock *ManualClock
+	w     *Stopwatch
+	suite.Suite
+}
+
+func (js *jsonSuite) SetupTest() {
+	js.clock = NewManualClock(time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC))
+	js.w = New("handler", nil, js.clock)
+
+	child := js.w.Child("db")
+	_ = js.w.Start()
+	_ = js.w.Begin("request")
+	js.clock.Advance(time.Millisecond)
+	_ = js.w.Lap("db", "users table")
+	_ = child.Start()
+	b, _ := js.w.Fork("cache")
+	js.clock.Advance(1500 * time.Microsecond)
+	_ = b.Join()
+	_ = child.Lap("query", "")
+	js.clock.Advance(3 * time.Millisecond)
+	_ = child.Stop()
+	_ = js.w.Pause()
+	js.clock.Advance(time.Second)
+	_ = js.w.Resume()
+	_ = js.w.Lap("render", "")
+	js.clock.Advance(2 * time.Millisecond)
+}
+
+func (js *jsonSuite) TestReportJSON_Success_RoundTrip() {
+	_ = js.w.End("request")
+	_ = js.w.Stop()
+	rpt, err := js.w.Report()
+	assert.Nil(js.T(), err)
+
+	data, err := json.Marshal(rpt)
+	assert.Nil(js.T(), err)
+
+	var decoded Report
+	err = json.Unmarshal(data, &decoded)
+	assert.Nil(js.T(), err)
+	assert.Equal(js.T(), rpt, decoded)
+}
+
+func (js *jsonSuite) TestReportJSON_Success_RoundTripSnapshot() {
+	rpt, err := js.w.Snapshot()
+	assert.Nil(js.T(), err)
+
+	data, err := json.Marshal(rpt)
+	assert.Nil(js.T(), err)
+	var wire map[string]interface{}
+	_ = json.Unmarshal(data, &wire)
+	_, exists := wire["stopped_at"]
+	assert.False(js.T(), exists)
+
+	var decoded Report
+	err = json.Unmarshal(data, &decoded)
+	assert.Nil(js.T(), err)
+	assert.Equal(js.T(), rpt, decoded)
+	assert.True(js.T(), decoded.StoppedAt.IsZero())
+}
+
+func (js *jsonSuite) TestReportJSON_Success_WireFormat() {
+	_ = js.w.Stop()
+	rpt, _ := js.w.Report()
+	data, err := json.Marshal(rpt)
+	assert.Nil(js.T(), err)
+
+	var wire map[string]interface{}
+	err = json.Unmarshal(data, &wire)
+	assert.Nil(js.T(), err)
+	assert.Equal(js.T(), float64(ReportSchemaVersion), wire["version"])
+	assert.Equal(js.T(), "handler", wire["name"])
+	assert.Equal(js.T(), "2019-01-01T00:00:00Z", wire["started_at"])
+	assert.Equal(js.T(), "2019-01-01T00:00:01.0075Z", wire["stopped_at"])
+	assert.Equal(js.T(), float64(7500*time.Microsecond), wire["duration_ns"])
+	assert.Equal(js.T(), "7.5ms", wire["duration"])
+	assert.Equal(js.T(), "1s", wire["paused"])
+
+	splits := wire["splits"].([]interface{})
+	assert.Equal(js.T(), 3, len(splits))
+	dbSplit := splits[1].(map[string]interface{})
+	assert.Equal(js.T(), "db", dbSplit["name"])
+	assert.Equal(js.T(), "users table", dbSplit["comment"])
+	assert.Equal(js.T(), float64(4500*time.Microsecond), dbSplit["duration_ns"])
+	assert.Equal(js.T(), "4.5ms", dbSplit["duration"])
+	assert.Equal(js.T(), "2019-01-01T00:00:00.001Z", dbSplit["start"])
+
+	children := wire["children"].([]interface{})
+	assert.Equal(js.T(), 1, len(children))
+	assert.Equal(js.T(), "db", children[0].(map[string]interface{})["name"])
+}
+
+func (js *jsonSuite) TestReportJSON_Error_UnsupportedVersion() {
+	var decoded Report
+	err := json.Unmarshal([]byte(`{"version":2,"name":"handler"}`), &decoded)
+	assert.NotNil(js.T(), err)
+	assert.IsType(js.T(), &StopwatchErr{}, err)
+	assert.Equal(js.T(), NewUnsupportedVersionErr(2).Error(), err.Error())
+	assert.Zero(js.T(), decoded)
+}
+
+func (js *jsonSuite) TestReportJSON_Error_Malformed() {
+	var decoded Report
+	err := json.Unmarshal([]byte(`{"version":1,"splits":[{"duration_ns":"slow"}]}`), &decoded)
+	assert.NotNil(js.T(), err)
+	assert.Zero(js.T(), decoded)
+}
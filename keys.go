@@ -0,0 +1,45 @@
+package stopwatch
+
+import (
+	"errors"
+	"regexp"
+)
+
+type KeyPolicy func(lapKey string) error
+
+//reservedKeys only covers keys stored in records; pause, fork and the like
+//are Logger keys alone and stay usable as lap names.
+var reservedKeys = map[key]bool{
+	start: true,
+	stop:  true,
+}
+
+var dottedLowercase = regexp.MustCompile(`^[a-z][a-z0-9_]*(\.[a-z][a-z0-9_]*)*$`)
+
+func DottedLowercaseKeys(lapKey string) error {
+	if !dottedLowercase.MatchString(lapKey) {
+		return errors.New("key must be dot separated lowercase identifiers (e.g. db.query)")
+	}
+
+	return nil
+}
+
+func (w *Stopwatch) validateKey(lapKey string) error {
+	if lapKey == "" {
+		return NewInvalidKeyErr(w, lapKey, "key is empty")
+	}
+
+	if reservedKeys[newKey(lapKey)] {
+		return NewInvalidKeyErr(w, lapKey, "key is reserved")
+	}
+
+	if w.KeyPolicy == nil {
+		return nil
+	}
+
+	if err := w.KeyPolicy(lapKey); err != nil {
+		return NewInvalidKeyErr(w, lapKey, err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package stopwatch
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+//Success
+// - Policy accepts key
+// - Logger-only keys are valid laps
+//Error
+// - EmptyKey
+// - ReservedKey
+// - PolicyRejectsKey
+func TestKeyValidation(t *testing.T) {
+	kvs := new(keyValidationSuite)
+	suite.Run(t, kvs)
+}
+
+type keyValidationSuite struct {
+	w      *Stopwatch
+	logger *testLogger
+	suite.Suite
+}
+
+func (kvs *keyValidationSuite) SetupTest() {
+	kvs.logger = &testLogger{}
+	kvs.w = New("test", kvs.logger, nil)
+	_ = kvs.w.Start()
+}
+
+func (kvs *keyValidationSuite) TestLap_Success_PolicyAcceptsKey() {
+	kvs.w.KeyPolicy = DottedLowercaseKeys
+	err := kvs.w.Lap("db.query", "")
+	assert.Nil(kvs.T(), err)
+	assert.Equal(kvs.T(), 2, len(kvs.w.records))
+}
+
+func (kvs *keyValidationSuite) TestLap_Success_LoggerOnlyKeys() {
+	for _, k := range []key{pause, resume, fork, join, intervalBegin, intervalEnd} {
+		assert.Nil(kvs.T(), kvs.w.Lap(k.String(), ""))
+	}
+	assert.Nil(kvs.T(), kvs.w.Stop())
+
+	rpt, err := kvs.w.Report()
+	assert.Nil(kvs.T(), err)
+	assert.Equal(kvs.T(), 7, len(rpt.Splits))
+	assert.Equal(kvs.T(), "end", rpt.Splits[6].Name)
+}
+
+func (kvs *keyValidationSuite) TestLap_Error_EmptyKey() {
+	err := kvs.w.Lap("", "")
+	assert.NotNil(kvs.T(), err)
+	assert.IsType(kvs.T(), &StopwatchErr{}, err)
+	assert.Equal(kvs.T(), NewInvalidKeyErr(kvs.w, "", "key is empty").Error(), err.Error())
+	assert.Equal(kvs.T(), 1, len(kvs.w.records))
+}
+
+func (kvs *keyValidationSuite) TestLap_Error_ReservedKey() {
+	for _, k := range []key{start, stop} {
+		err := kvs.w.Lap(k.String(), "")
+		assert.NotNil(kvs.T(), err)
+		assert.IsType(kvs.T(), &StopwatchErr{}, err)
+		assert.Equal(kvs.T(), NewInvalidKeyErr(kvs.w, k.String(), "key is reserved").Error(), err.Error())
+	}
+
+	assert.Equal(kvs.T(), 1, len(kvs.w.records))
+	assert.True(kvs.T(), kvs.w.started())
+	assert.False(kvs.T(), kvs.w.stopped())
+
+	expectedNumOfLogs := 1
+	assert.Equal(kvs.T(), expectedNumOfLogs, len(kvs.logger.logs))
+}
+
+func (kvs *keyValidationSuite) TestLap_Error_PolicyRejectsKey() {
+	policyErr := errors.New("no shouting")
+	kvs.w.KeyPolicy = func(lapKey string) error {
+		return policyErr
+	}
+
+	err := kvs.w.Lap("DB", "")
+	assert.NotNil(kvs.T(), err)
+	assert.IsType(kvs.T(), &StopwatchErr{}, err)
+	assert.Equal(kvs.T(), NewInvalidKeyErr(kvs.w, "DB", policyErr.Error()).Error(), err.Error())
+}
+
+func TestDottedLowercaseKeys(t *testing.T) {
+	valid := []string{"db", "db.query", "http.handler_v2", "a.b.c"}
+	for _, k := range valid {
+		assert.Nil(t, DottedLowercaseKeys(k), k)
+	}
+
+	invalid := []string{"", "DB", "db.", ".db", "db..query", "2db", "db query", "db-query"}
+	for _, k := range invalid {
+		assert.NotNil(t, DottedLowercaseKeys(k), k)
+	}
+}
@@ -0,0 +1,85 @@
+package stopwatch
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+var markdownReplacer = strings.NewReplacer("|", `\|`, "\n", " ", "\r", " ")
+
+func WriteMarkdown(w io.Writer, r Report) error {
+	lines := []string{
+		fmt.Sprintf("### %s", markdownCell(r.Name)),
+		"",
+		"| Split | Comment | Duration | % |",
+		"| --- | --- | ---: | ---: |",
+	}
+	for _, split := range r.Splits {
+		lines = append(lines, fmt.Sprintf("| %s | %s | %s | %s |",
+			markdownCell(split.Name),
+			markdownCell(split.Comment),
+			split.Duration,
+			formatPercent(split.Duration, r.Duration),
+		))
+	}
+	lines = append(lines, fmt.Sprintf("| **Total** | | **%s** | |", r.Duration))
+
+	return writeLines(w, lines)
+}
+
+//WriteMarkdownComparison sums repeated splits by name and lines base and
+//head up side by side; splits only present on one side show as added or
+//removed.
+func WriteMarkdownComparison(w io.Writer, base, head Report) error {
+	c := Compare(base, head, Tolerance{})
+	lines := []string{
+		fmt.Sprintf("### %s: base vs head", markdownCell(head.Name)),
+		"",
+		"| Split | Base | Head | Delta | Change |",
+		"| --- | ---: | ---: | ---: | ---: |",
+	}
+	for _, d := range c.Splits {
+		lines = append(lines, markdownDeltaRow(markdownCell(d.Name), d))
+	}
+	lines = append(lines, markdownDeltaRow("**Total**", c.Total))
+
+	return writeLines(w, lines)
+}
+
+func markdownDeltaRow(name string, d SplitDelta) string {
+	switch {
+	case d.Added:
+		return fmt.Sprintf("| %s | - | %s | %s | added |", name, d.Head, formatDelta(d.Delta))
+	case d.Removed:
+		return fmt.Sprintf("| %s | %s | - | %s | removed |", name, d.Base, formatDelta(d.Delta))
+	}
+
+	return fmt.Sprintf("| %s | %s | %s | %s | %s |", name, d.Base, d.Head, formatDelta(d.Delta), formatChange(d.Base, d.Head))
+}
+
+func formatDelta(d time.Duration) string {
+	if d > 0 {
+		return "+" + d.String()
+	}
+
+	return d.String()
+}
+
+func formatChange(base, head time.Duration) string {
+	if base == 0 {
+		return "n/a"
+	}
+
+	return fmt.Sprintf("%+.1f%%", 100*float64(head-base)/float64(base))
+}
+
+func markdownCell(s string) string {
+	return markdownReplacer.Replace(s)
+}
+
+func writeLines(w io.Writer, lines []string) error {
+	_, err := io.WriteString(w, strings.Join(lines, "\n")+"\n")
+	return err
+}
@@ -0,0 +1,90 @@
+package stopwatch
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"strings"
+	"testing"
+	"time"
+)
+
+//Success
+// - Report
+// - Cells are escaped
+// - Comparison with repeated, added and removed splits
+func TestWriteMarkdown(t *testing.T) {
+	ms := new(markdownSuite)
+	suite.Run(t, ms)
+}
+
+type markdownSuite struct {
+	reports []Report
+	buf     *bytes.Buffer
+	suite.Suite
+}
+
+func (ms *markdownSuite) SetupTest() {
+	ms.reports = fixtureReports(2)
+	ms.buf = &bytes.Buffer{}
+}
+
+func (ms *markdownSuite) TestWriteMarkdown_Success() {
+	err := WriteMarkdown(ms.buf, ms.reports[0])
+	assert.Nil(ms.T(), err)
+
+	expected := strings.Join([]string{
+		"### handler",
+		"",
+		"| Split | Comment | Duration | % |",
+		"| --- | --- | ---: | ---: |",
+		"| start |  | 1ms | 10.0% |",
+		"| db | users table | 7ms | 70.0% |",
+		"| render |  | 2ms | 20.0% |",
+		"| **Total** | | **10ms** | |",
+		"",
+	}, "\n")
+	assert.Equal(ms.T(), expected, ms.buf.String())
+}
+
+func (ms *markdownSuite) TestWriteMarkdown_Success_Escaped() {
+	ms.reports[0].Splits[1].Comment = "a|b"
+	err := WriteMarkdown(ms.buf, ms.reports[0])
+	assert.Nil(ms.T(), err)
+	assert.Contains(ms.T(), ms.buf.String(), `| db | a\|b | 7ms | 70.0% |`)
+}
+
+func (ms *markdownSuite) TestWriteMarkdownComparison_Success() {
+	base, head := ms.reports[0], ms.reports[1]
+	base.Splits = append(base.Splits,
+		Split{Name: "db", Duration: 500 * time.Microsecond},
+		Split{Name: "cache", Duration: time.Millisecond},
+	)
+	base.Duration += 1500 * time.Microsecond
+	head.Splits = append(head.Splits, Split{Name: "retry", Duration: 2 * time.Millisecond})
+	head.Duration += 2 * time.Millisecond
+
+	err := WriteMarkdownComparison(ms.buf, base, head)
+	assert.Nil(ms.T(), err)
+
+	expected := strings.Join([]string{
+		"### handler: base vs head",
+		"",
+		"| Split | Base | Head | Delta | Change |",
+		"| --- | ---: | ---: | ---: | ---: |",
+		"| start | 1ms | 1ms | 0s | +0.0% |",
+		"| db | 7.5ms | 8ms | +500µs | +6.7% |",
+		"| render | 2ms | 2ms | 0s | +0.0% |",
+		"| cache | 1ms | - | -1ms | removed |",
+		"| retry | - | 2ms | +2ms | added |",
+		"| **Total** | 11.5ms | 13ms | +1.5ms | +13.0% |",
+		"",
+	}, "\n")
+	assert.Equal(ms.T(), expected, ms.buf.String())
+}
+
+func TestFormatChange(t *testing.T) {
+	assert.Equal(t, "n/a", formatChange(0, time.Second))
+	assert.Equal(t, "+50.0%", formatChange(2*time.Second, 3*time.Second))
+	assert.Equal(t, "-50.0%", formatChange(2*time.Second, time.Second))
+}
@@ -0,0 +1,231 @@
+package stopwatch
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+//DefaultPrometheusBuckets mirrors the Prometheus client library's defaults.
+var DefaultPrometheusBuckets = []time.Duration{
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+var DefaultPrometheusQuantiles = []float64{0.5, 0.9, 0.99}
+
+var labelReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+//PrometheusHandler serves observed Reports in the Prometheus text exposition
+//format: split durations as a histogram and run durations as a summary, both
+//labelled by stopwatch name.
+type PrometheusHandler struct {
+	quantiles []float64
+	buckets   []time.Duration
+	splits    map[splitSeries]*promHistogram
+	runs      map[string]*promSummary
+
+	pl *sync.Mutex
+}
+
+type splitSeries struct {
+	stopwatch string
+	split     string
+}
+
+type promHistogram struct {
+	counts []uint64
+	count  uint64
+	sum    time.Duration
+}
+
+type promSummary struct {
+	digest *Digest
+	count  uint64
+	sum    time.Duration
+}
+
+//NewPrometheusHandler sorts buckets and drops repeated and non-positive
+//bounds, using DefaultPrometheusBuckets when none are left.
+func NewPrometheusHandler(buckets []time.Duration) *PrometheusHandler {
+	sorted := make([]time.Duration, 0, len(buckets))
+	for _, bound := range buckets {
+		if bound > 0 {
+			sorted = append(sorted, bound)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	deduped := make([]time.Duration, 0, len(sorted))
+	for _, bound := range sorted {
+		if len(deduped) == 0 || bound != deduped[len(deduped)-1] {
+			deduped = append(deduped, bound)
+		}
+	}
+	if len(deduped) == 0 {
+		deduped = append(deduped, DefaultPrometheusBuckets...)
+	}
+
+	return &PrometheusHandler{
+		quantiles: append([]float64(nil), DefaultPrometheusQuantiles...),
+		buckets:   deduped,
+		splits:    make(map[splitSeries]*promHistogram),
+		runs:      make(map[string]*promSummary),
+
+		pl: &sync.Mutex{},
+	}
+}
+
+//SetQuantiles replaces the run summary's quantiles, leaving them unchanged
+//when any lies outside [0, 1].
+func (p *PrometheusHandler) SetQuantiles(quantiles []float64) error {
+	for _, q := range quantiles {
+		if !(q >= 0 && q <= 1) {
+			return NewInvalidQuantileErr(q)
+		}
+	}
+
+	p.pl.Lock()
+	defer p.pl.Unlock()
+
+	p.quantiles = append([]float64(nil), quantiles...)
+	return nil
+}
+
+//Observe records every split and the run duration of r, then of each child
+//under the child's own name.
+func (p *PrometheusHandler) Observe(r Report) {
+	p.pl.Lock()
+	defer p.pl.Unlock()
+
+	p.observe(r)
+}
+
+func (p *PrometheusHandler) observe(r Report) {
+	for _, split := range r.Splits {
+		series := splitSeries{stopwatch: r.Name, split: split.Name}
+		h, ok := p.splits[series]
+		if !ok {
+			h = &promHistogram{counts: make([]uint64, len(p.buckets))}
+			p.splits[series] = h
+		}
+		//counts are per bucket here and made cumulative when written
+		idx := sort.Search(len(p.buckets), func(i int) bool { return split.Duration <= p.buckets[i] })
+		if idx < len(p.buckets) {
+			h.counts[idx]++
+		}
+		h.count++
+		h.sum += split.Duration
+	}
+
+	s, ok := p.runs[r.Name]
+	if !ok {
+		s = &promSummary{digest: NewDigest(DefaultCompression)}
+		p.runs[r.Name] = s
+	}
+	s.digest.AddDuration(r.Duration)
+	s.count++
+	s.sum += r.Duration
+
+	for _, child := range r.Children {
+		p.observe(child)
+	}
+}
+
+//ServeHTTP answers with a 500 and no partial exposition when WriteTo fails.
+func (p *PrometheusHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	buf := &bytes.Buffer{}
+	if _, err := p.WriteTo(buf); err != nil {
+		http.Error(rw, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	rw.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	rw.Write(buf.Bytes())
+}
+
+//WriteTo writes the exposition with series sorted by stopwatch, then split.
+func (p *PrometheusHandler) WriteTo(w io.Writer) (int64, error) {
+	p.pl.Lock()
+	defer p.pl.Unlock()
+
+	lines := make([]string, 0)
+	if len(p.splits) > 0 {
+		lines = append(lines,
+			"# HELP stopwatch_split_duration_seconds Duration of stopwatch splits.",
+			"# TYPE stopwatch_split_duration_seconds histogram",
+		)
+	}
+	series := make([]splitSeries, 0, len(p.splits))
+	for s := range p.splits {
+		series = append(series, s)
+	}
+	sort.Slice(series, func(i, j int) bool {
+		if series[i].stopwatch != series[j].stopwatch {
+			return series[i].stopwatch < series[j].stopwatch
+		}
+		return series[i].split < series[j].split
+	})
+	for _, s := range series {
+		h := p.splits[s]
+		labels := fmt.Sprintf(`stopwatch="%s",split="%s"`, labelReplacer.Replace(s.stopwatch), labelReplacer.Replace(s.split))
+		var cumulative uint64
+		for i, bound := range p.buckets {
+			cumulative += h.counts[i]
+			lines = append(lines, fmt.Sprintf(`stopwatch_split_duration_seconds_bucket{%s,le="%s"} %d`, labels, formatSeconds(bound), cumulative))
+		}
+		lines = append(lines,
+			fmt.Sprintf(`stopwatch_split_duration_seconds_bucket{%s,le="+Inf"} %d`, labels, h.count),
+			fmt.Sprintf("stopwatch_split_duration_seconds_sum{%s} %s", labels, formatSeconds(h.sum)),
+			fmt.Sprintf("stopwatch_split_duration_seconds_count{%s} %d", labels, h.count),
+		)
+	}
+
+	if len(p.runs) > 0 {
+		lines = append(lines,
+			"# HELP stopwatch_run_duration_seconds Duration of stopwatch runs.",
+			"# TYPE stopwatch_run_duration_seconds summary",
+		)
+	}
+	names := make([]string, 0, len(p.runs))
+	for name := range p.runs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		s := p.runs[name]
+		labels := fmt.Sprintf(`stopwatch="%s"`, labelReplacer.Replace(name))
+		for _, q := range p.quantiles {
+			lines = append(lines, fmt.Sprintf(`stopwatch_run_duration_seconds{%s,quantile="%s"} %s`,
+				labels, strconv.FormatFloat(q, 'g', -1, 64), formatSeconds(s.digest.QuantileDuration(q))))
+		}
+		lines = append(lines,
+			fmt.Sprintf("stopwatch_run_duration_seconds_sum{%s} %s", labels, formatSeconds(s.sum)),
+			fmt.Sprintf("stopwatch_run_duration_seconds_count{%s} %d", labels, s.count),
+		)
+	}
+
+	if len(lines) == 0 {
+		return 0, nil
+	}
+	n, err := io.WriteString(w, strings.Join(lines, "\n")+"\n")
+	return int64(n), err
+}
+
+func formatSeconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'g', -1, 64)
+}
@@ -0,0 +1,197 @@
+package stopwatch
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+//Success
+// - Exposition of the shared fixture, children and escaped labels included
+// - Empty handler
+// - Repeated and non-positive buckets are dropped
+// - Handlers do not share the defaults
+// - Quantiles can be replaced
+// - Concurrent observe and write
+//Error
+// - Writer fails
+// - Invalid quantiles are rejected up front
+func TestPrometheusHandler(t *testing.T) {
+	ps := new(prometheusSuite)
+	suite.Run(t, ps)
+}
+
+type prometheusSuite struct {
+	p       *PrometheusHandler
+	reports []Report
+	suite.Suite
+}
+
+func (ps *prometheusSuite) SetupTest() {
+	ps.p = NewPrometheusHandler(nil)
+	ps.reports = fixtureReports(2)
+	ps.reports[1].Children[0].Name = `cache "l2"`
+}
+
+func (ps *prometheusSuite) TestServeHTTP_Success() {
+	p := NewPrometheusHandler([]time.Duration{10 * time.Millisecond, 2 * time.Millisecond})
+	for _, rpt := range ps.reports {
+		p.Observe(rpt)
+	}
+
+	rec := httptest.NewRecorder()
+	p.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(ps.T(), http.StatusOK, rec.Code)
+	assert.Equal(ps.T(), "text/plain; version=0.0.4; charset=utf-8", rec.Header().Get("Content-Type"))
+
+	expected := strings.Join([]string{
+		"# HELP stopwatch_split_duration_seconds Duration of stopwatch splits.",
+		"# TYPE stopwatch_split_duration_seconds histogram",
+		`stopwatch_split_duration_seconds_bucket{stopwatch="cache \"l2\"",split="query;select",le="0.002"} 0`,
+		`stopwatch_split_duration_seconds_bucket{stopwatch="cache \"l2\"",split="query;select",le="0.01"} 1`,
+		`stopwatch_split_duration_seconds_bucket{stopwatch="cache \"l2\"",split="query;select",le="+Inf"} 1`,
+		`stopwatch_split_duration_seconds_sum{stopwatch="cache \"l2\"",split="query;select"} 0.007`,
+		`stopwatch_split_duration_seconds_count{stopwatch="cache \"l2\"",split="query;select"} 1`,
+		`stopwatch_split_duration_seconds_bucket{stopwatch="cache \"l2\"",split="start",le="0.002"} 1`,
+		`stopwatch_split_duration_seconds_bucket{stopwatch="cache \"l2\"",split="start",le="0.01"} 1`,
+		`stopwatch_split_duration_seconds_bucket{stopwatch="cache \"l2\"",split="start",le="+Inf"} 1`,
+		`stopwatch_split_duration_seconds_sum{stopwatch="cache \"l2\"",split="start"} 0.002`,
+		`stopwatch_split_duration_seconds_count{stopwatch="cache \"l2\"",split="start"} 1`,
+		`stopwatch_split_duration_seconds_bucket{stopwatch="db",split="query;select",le="0.002"} 0`,
+		`stopwatch_split_duration_seconds_bucket{stopwatch="db",split="query;select",le="0.01"} 1`,
+		`stopwatch_split_duration_seconds_bucket{stopwatch="db",split="query;select",le="+Inf"} 1`,
+		`stopwatch_split_duration_seconds_sum{stopwatch="db",split="query;select"} 0.006`,
+		`stopwatch_split_duration_seconds_count{stopwatch="db",split="query;select"} 1`,
+		`stopwatch_split_duration_seconds_bucket{stopwatch="db",split="start",le="0.002"} 1`,
+		`stopwatch_split_duration_seconds_bucket{stopwatch="db",split="start",le="0.01"} 1`,
+		`stopwatch_split_duration_seconds_bucket{stopwatch="db",split="start",le="+Inf"} 1`,
+		`stopwatch_split_duration_seconds_sum{stopwatch="db",split="start"} 0.002`,
+		`stopwatch_split_duration_seconds_count{stopwatch="db",split="start"} 1`,
+		`stopwatch_split_duration_seconds_bucket{stopwatch="handler",split="db",le="0.002"} 0`,
+		`stopwatch_split_duration_seconds_bucket{stopwatch="handler",split="db",le="0.01"} 2`,
+		`stopwatch_split_duration_seconds_bucket{stopwatch="handler",split="db",le="+Inf"} 2`,
+		`stopwatch_split_duration_seconds_sum{stopwatch="handler",split="db"} 0.015`,
+		`stopwatch_split_duration_seconds_count{stopwatch="handler",split="db"} 2`,
+		`stopwatch_split_duration_seconds_bucket{stopwatch="handler",split="render",le="0.002"} 2`,
+		`stopwatch_split_duration_seconds_bucket{stopwatch="handler",split="render",le="0.01"} 2`,
+		`stopwatch_split_duration_seconds_bucket{stopwatch="handler",split="render",le="+Inf"} 2`,
+		`stopwatch_split_duration_seconds_sum{stopwatch="handler",split="render"} 0.004`,
+		`stopwatch_split_duration_seconds_count{stopwatch="handler",split="render"} 2`,
+		`stopwatch_split_duration_seconds_bucket{stopwatch="handler",split="start",le="0.002"} 2`,
+		`stopwatch_split_duration_seconds_bucket{stopwatch="handler",split="start",le="0.01"} 2`,
+		`stopwatch_split_duration_seconds_bucket{stopwatch="handler",split="start",le="+Inf"} 2`,
+		`stopwatch_split_duration_seconds_sum{stopwatch="handler",split="start"} 0.002`,
+		`stopwatch_split_duration_seconds_count{stopwatch="handler",split="start"} 2`,
+		"# HELP stopwatch_run_duration_seconds Duration of stopwatch runs.",
+		"# TYPE stopwatch_run_duration_seconds summary",
+		`stopwatch_run_duration_seconds{stopwatch="cache \"l2\"",quantile="0.5"} 0.009`,
+		`stopwatch_run_duration_seconds{stopwatch="cache \"l2\"",quantile="0.9"} 0.009`,
+		`stopwatch_run_duration_seconds{stopwatch="cache \"l2\"",quantile="0.99"} 0.009`,
+		`stopwatch_run_duration_seconds_sum{stopwatch="cache \"l2\""} 0.009`,
+		`stopwatch_run_duration_seconds_count{stopwatch="cache \"l2\""} 1`,
+		`stopwatch_run_duration_seconds{stopwatch="db",quantile="0.5"} 0.008`,
+		`stopwatch_run_duration_seconds{stopwatch="db",quantile="0.9"} 0.008`,
+		`stopwatch_run_duration_seconds{stopwatch="db",quantile="0.99"} 0.008`,
+		`stopwatch_run_duration_seconds_sum{stopwatch="db"} 0.008`,
+		`stopwatch_run_duration_seconds_count{stopwatch="db"} 1`,
+		`stopwatch_run_duration_seconds{stopwatch="handler",quantile="0.5"} 0.0105`,
+		`stopwatch_run_duration_seconds{stopwatch="handler",quantile="0.9"} 0.011`,
+		`stopwatch_run_duration_seconds{stopwatch="handler",quantile="0.99"} 0.011`,
+		`stopwatch_run_duration_seconds_sum{stopwatch="handler"} 0.021`,
+		`stopwatch_run_duration_seconds_count{stopwatch="handler"} 2`,
+		"",
+	}, "\n")
+	assert.Equal(ps.T(), expected, rec.Body.String())
+}
+
+func (ps *prometheusSuite) TestWriteTo_Success_Empty() {
+	buf := &bytes.Buffer{}
+	n, err := ps.p.WriteTo(buf)
+	assert.Nil(ps.T(), err)
+	assert.Zero(ps.T(), n)
+	assert.Equal(ps.T(), DefaultPrometheusBuckets, ps.p.buckets)
+}
+
+func (ps *prometheusSuite) TestNewPrometheusHandler_Success_CleansBuckets() {
+	p := NewPrometheusHandler([]time.Duration{10 * time.Millisecond, 0, 2 * time.Millisecond, -time.Second, 10 * time.Millisecond})
+	assert.Equal(ps.T(), []time.Duration{2 * time.Millisecond, 10 * time.Millisecond}, p.buckets)
+
+	p.Observe(ps.reports[0])
+	buf := &bytes.Buffer{}
+	p.WriteTo(buf)
+	assert.Equal(ps.T(), 1, strings.Count(buf.String(), `split="db",le="0.01"}`))
+
+	p = NewPrometheusHandler([]time.Duration{0, -time.Second})
+	assert.Equal(ps.T(), DefaultPrometheusBuckets, p.buckets)
+}
+
+func (ps *prometheusSuite) TestNewPrometheusHandler_Success_OwnsDefaults() {
+	ps.p.quantiles[0] = 0.75
+	ps.p.buckets[0] = time.Hour
+
+	p := NewPrometheusHandler(nil)
+	assert.Equal(ps.T(), 0.5, p.quantiles[0])
+	assert.Equal(ps.T(), 0.5, DefaultPrometheusQuantiles[0])
+	assert.Equal(ps.T(), 5*time.Millisecond, DefaultPrometheusBuckets[0])
+}
+
+func (ps *prometheusSuite) TestSetQuantiles_Success() {
+	quantiles := []float64{0, 1}
+	assert.Nil(ps.T(), ps.p.SetQuantiles(quantiles))
+	quantiles[0] = 0.5
+	ps.p.Observe(ps.reports[0])
+
+	buf := &bytes.Buffer{}
+	ps.p.WriteTo(buf)
+	assert.Contains(ps.T(), buf.String(), `stopwatch_run_duration_seconds{stopwatch="handler",quantile="0"} 0.01`)
+	assert.Contains(ps.T(), buf.String(), `stopwatch_run_duration_seconds{stopwatch="handler",quantile="1"} 0.01`)
+	assert.NotContains(ps.T(), buf.String(), `quantile="0.5"`)
+}
+
+func (ps *prometheusSuite) TestObserve_Success_Concurrency() {
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				ps.p.Observe(ps.reports[0])
+				ps.p.WriteTo(&bytes.Buffer{})
+			}
+		}()
+	}
+	wg.Wait()
+
+	buf := &bytes.Buffer{}
+	ps.p.WriteTo(buf)
+	assert.Contains(ps.T(), buf.String(), `stopwatch_split_duration_seconds_count{stopwatch="handler",split="db"} 800`)
+	assert.Contains(ps.T(), buf.String(), `stopwatch_run_duration_seconds_count{stopwatch="db"} 800`)
+}
+
+func (ps *prometheusSuite) TestWriteTo_Error() {
+	ps.p.Observe(ps.reports[0])
+	_, err := ps.p.WriteTo(&failingWriter{})
+	assert.NotNil(ps.T(), err)
+}
+
+func (ps *prometheusSuite) TestSetQuantiles_Error() {
+	ps.p.Observe(ps.reports[0])
+	err := ps.p.SetQuantiles([]float64{0.5, 1.5})
+	assert.Equal(ps.T(), NewInvalidQuantileErr(1.5).Error(), err.Error())
+	err = ps.p.SetQuantiles([]float64{math.NaN()})
+	assert.Equal(ps.T(), NewInvalidQuantileErr(math.NaN()).Error(), err.Error())
+
+	//the rejected quantiles leave the exposition untouched
+	rec := httptest.NewRecorder()
+	ps.p.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(ps.T(), http.StatusOK, rec.Code)
+	assert.Contains(ps.T(), rec.Body.String(), `stopwatch_split_duration_seconds_count{stopwatch="handler",split="db"} 1`)
+	assert.Contains(ps.T(), rec.Body.String(), `stopwatch_run_duration_seconds{stopwatch="handler",quantile="0.99"} 0.01`)
+}
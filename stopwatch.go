@@ -2,18 +2,26 @@ package stopwatch
 
 import (
 	"context"
-	"math"
 	"sync"
 	"time"
 )
 
 const (
-	start key = "start"
-	stop  key = "stop"
+	start  key = "start"
+	stop   key = "stop"
+	pause  key = "pause"
+	resume key = "resume"
+	fork   key = "fork"
+	join   key = "join"
+
+	intervalBegin key = "begin"
+	intervalEnd   key = "end"
 )
 
 const ctxStopwatch = "stopwatch"
 
+const DefaultHistorySize = 16
+
 type key string
 
 func (k key) String() string {
@@ -21,26 +29,65 @@ func (k key) String() string {
 }
 
 type record struct {
-	ts      int64
+	key     key
+	ts      time.Time
 	comment string
 }
-type entries map[key]record
+
+type interval struct {
+	begin time.Time
+	end   time.Time
+}
+
+type namedInterval struct {
+	name string
+	interval
+}
+
+type Interval struct {
+	Name     string
+	Start    time.Time
+	End      time.Time
+	Duration time.Duration
+}
 
 type Split struct {
 	Name     string
 	Comment  string
+	Start    time.Time
+	End      time.Time
 	Duration time.Duration
 }
 
+type SplitSummary struct {
+	Name  string
+	Count int
+	Total time.Duration
+	Min   time.Duration
+	Max   time.Duration
+	Mean  time.Duration
+}
+
 type Stopwatch struct {
-	Name    string
-	Logger  Logger
-	running bool
-	keys    []key
-	records entries
+	Name        string
+	Logger      Logger
+	Clock       Clock
+	KeyPolicy   KeyPolicy
+	HistorySize int
+	running     bool
+	run         int
+	records     []record
+	pauses      []interval
+	branches    []namedInterval
+	intervals   []namedInterval
+	parent      *Stopwatch
+	children    []*Stopwatch
+	history     []Report
 
 	rl *sync.Mutex
 }
+//Log is called after the stopwatch has released its lock, so it may be called
+//from several goroutines at once.
 type Logger interface {
 	Log(timestamp int64, key string, comment string)
 }
@@ -49,53 +96,183 @@ type nopLogger struct{}
 
 func (l *nopLogger) Log(_ int64, _, _ string) {}
 
-func New(name string, logger Logger) *Stopwatch {
+func New(name string, logger Logger, clock Clock) *Stopwatch {
 	if logger == nil {
 		logger = &nopLogger{}
 	}
 
+	if clock == nil {
+		clock = &systemClock{}
+	}
+
 	return &Stopwatch{
-		Name:    name,
-		Logger:  logger,
-		keys:    make([]key, 0),
-		records: make(map[key]record),
+		Name:        name,
+		Logger:      logger,
+		Clock:       clock,
+		HistorySize: DefaultHistorySize,
+		records:     make([]record, 0),
+		history:     make([]Report, 0),
 
 		rl: &sync.Mutex{},
 	}
 }
 
+//Child creates a stopwatch for a sub-operation; its report is nested under
+//this stopwatch's Report once it has been started.
+func (w *Stopwatch) Child(name string) *Stopwatch {
+	child := New(name, w.Logger, w.Clock)
+	child.KeyPolicy = w.KeyPolicy
+	child.parent = w
+
+	w.rl.Lock()
+	defer w.rl.Unlock()
+	w.children = append(w.children, child)
+	return child
+}
+
+func (w *Stopwatch) Parent() *Stopwatch {
+	return w.parent
+}
+
 func (w *Stopwatch) Start() error {
-	if w.stopped() {
-		return NewAlreadyStoppedErr(w)
-	}
+	return w.stamp(func() (record, error) {
+		if w.stopped() {
+			return record{}, NewAlreadyStoppedErr(w)
+		}
 
-	if w.started() {
-		return NewAlreadyStartedErr(w)
-	}
+		if w.started() {
+			return record{}, NewAlreadyStartedErr(w)
+		}
 
-	w.setRunning(true)
-	w.keys = append(w.keys, start)
+		return w.start(), nil
+	})
+}
+
+func (w *Stopwatch) start() record {
+	w.running = true
 	startComment := ""
-	startRecord := newRecord(startComment)
-	w.records[start] = startRecord
-	w.Logger.Log(startRecord.ts, start.String(), startComment)
-	return nil
+	startRecord := newRecord(start, w.Clock.Now(), startComment)
+	w.records = append(w.records, startRecord)
+	return startRecord
 }
 
-func (w *Stopwatch) Lap(lapKey, lapComment string) error {
-	if w.stopped() {
-		return NewAlreadyStoppedErr(w)
-	}
-	if !w.started() {
-		return NewNotStartedErr(w)
+//stamp runs fn under rl and logs the record it returns only once rl is
+//released, so a Logger may call back into the stopwatch and a slow one does
+//not hold up other goroutines. Records are already ordered by then.
+func (w *Stopwatch) stamp(fn func() (record, error)) error {
+	w.rl.Lock()
+	stamped, err := fn()
+	w.rl.Unlock()
+	if err != nil {
+		return err
 	}
-	lk := newKey(lapKey)
-	w.keys = append(w.keys, key(lk))
-	w.records[lk] = newRecord(lapComment)
-	w.Logger.Log(time.Now().UTC().UnixNano(), lapKey, lapComment)
+
+	w.Logger.Log(stamped.ts.UnixNano(), stamped.key.String(), stamped.comment)
 	return nil
 }
 
+//Reset clears the current run so the stopwatch can be started again. A
+//stopped run is kept in History; an unfinished one is discarded.
+func (w *Stopwatch) Reset() {
+	w.rl.Lock()
+	defer w.rl.Unlock()
+
+	w.reset()
+}
+
+func (w *Stopwatch) Restart() {
+	w.stamp(func() (record, error) {
+		w.reset()
+		return w.start(), nil
+	})
+}
+
+func (w *Stopwatch) reset() {
+	if w.stopped() && w.HistorySize > 0 {
+		w.history = append(w.history, w.buildReport(w.records))
+		if overflow := len(w.history) - w.HistorySize; overflow > 0 {
+			w.history = append(w.history[:0:0], w.history[overflow:]...)
+		}
+	}
+
+	w.running = false
+	w.run++
+	w.records = make([]record, 0)
+	w.pauses = nil
+	w.branches = nil
+	w.intervals = nil
+	w.children = nil
+}
+
+func (w *Stopwatch) History() []Report {
+	w.rl.Lock()
+	defer w.rl.Unlock()
+
+	history := make([]Report, len(w.history))
+	copy(history, w.history)
+	return history
+}
+
+func (w *Stopwatch) Lap(lapKey, lapComment string) error {
+	return w.stamp(func() (record, error) {
+		if w.stopped() {
+			return record{}, NewAlreadyStoppedErr(w)
+		}
+		if !w.started() {
+			return record{}, NewNotStartedErr(w)
+		}
+		if err := w.validateKey(lapKey); err != nil {
+			return record{}, err
+		}
+		//stamping under rl keeps records ordered by timestamp when laps race
+		lapRecord := newRecord(newKey(lapKey), w.Clock.Now(), lapComment)
+		w.records = append(w.records, lapRecord)
+		return lapRecord, nil
+	})
+}
+
+func (w *Stopwatch) Pause() error {
+	return w.stamp(func() (record, error) {
+		if w.stopped() {
+			return record{}, NewAlreadyStoppedErr(w)
+		}
+
+		if !w.started() {
+			return record{}, NewNotStartedErr(w)
+		}
+
+		if w.paused() {
+			return record{}, NewAlreadyPausedErr(w)
+		}
+
+		w.running = false
+		ts := w.Clock.Now()
+		w.pauses = append(w.pauses, interval{begin: ts})
+		return newRecord(pause, ts, ""), nil
+	})
+}
+
+func (w *Stopwatch) Resume() error {
+	return w.stamp(func() (record, error) {
+		if w.stopped() {
+			return record{}, NewAlreadyStoppedErr(w)
+		}
+
+		if !w.started() {
+			return record{}, NewNotStartedErr(w)
+		}
+
+		if !w.paused() {
+			return record{}, NewNotPausedErr(w)
+		}
+
+		ts := w.Clock.Now()
+		w.pauses[len(w.pauses)-1].end = ts
+		w.running = true
+		return newRecord(resume, ts, ""), nil
+	})
+}
+
 func (w *Stopwatch) Running() bool {
 	w.rl.Lock()
 	defer w.rl.Unlock()
@@ -103,93 +280,283 @@ func (w *Stopwatch) Running() bool {
 }
 
 func (w *Stopwatch) Stop() error {
-	if w.stopped() {
-		return NewAlreadyStoppedErr(w)
+	return w.stamp(func() (record, error) {
+		if w.stopped() {
+			return record{}, NewAlreadyStoppedErr(w)
+		}
+
+		if !w.started() {
+			return record{}, NewNotStartedErr(w)
+		}
+
+		w.running = false
+
+		stopComment := ""
+		stopRecord := newRecord(stop, w.Clock.Now(), stopComment)
+		w.records = append(w.records, stopRecord)
+		if w.paused() {
+			w.pauses[len(w.pauses)-1].end = stopRecord.ts
+		}
+		return stopRecord, nil
+	})
+}
+
+func (w *Stopwatch) Report() (Report, error) {
+	w.rl.Lock()
+	defer w.rl.Unlock()
+
+	if !w.started() {
+		return Report{}, NewNotStartedErr(w)
 	}
 
+	if !w.stopped() {
+		return Report{}, NewNotStoppedErr(w)
+	}
+
+	return w.buildReport(w.records), nil
+}
+
+func (w *Stopwatch) Elapsed() (time.Duration, error) {
+	w.rl.Lock()
+	defer w.rl.Unlock()
+
 	if !w.started() {
-		return NewNotStartedErr(w)
+		return time.Duration(0), NewNotStartedErr(w)
 	}
 
-	w.setRunning(false)
-	w.keys = append(w.keys, stop)
+	begin := w.records[0].ts
+	end := w.records[len(w.records)-1].ts
+	if !w.stopped() {
+		end = begin.Add(w.Clock.Since(begin))
+	}
 
-	stopComment := ""
-	stopRecord := newRecord(stopComment)
-	w.records[stop] = stopRecord
-	w.Logger.Log(stopRecord.ts, stop.String(), stopComment)
-	return nil
+	return w.durationBetween(begin, end), nil
 }
 
-func (w *Stopwatch) Report() (Report, error) {
+//Snapshot reports a running stopwatch as if it were stopped now; the last lap
+//becomes an open split ending at the current time.
+func (w *Stopwatch) Snapshot() (Report, error) {
+	w.rl.Lock()
+	defer w.rl.Unlock()
+
 	if !w.started() {
 		return Report{}, NewNotStartedErr(w)
 	}
 
-	if !w.stopped() {
-		return Report{}, NewNotStoppedErr(w)
+	return w.snapshot(w.Clock.Now()), nil
+}
+
+//snapshot reports a started stopwatch as if it had been stopped at until,
+//leaving out anything recorded after it; one stopped by then reports as it is.
+func (w *Stopwatch) snapshot(until time.Time) Report {
+	if w.stopped() && !w.records[len(w.records)-1].ts.After(until) {
+		return w.buildReport(w.records)
 	}
 
-	duration, err := w.calculateDuration(start, stop)
-	if err != nil {
-		return Report{}, err
+	records := make([]record, 0, len(w.records)+1)
+	for _, r := range w.records {
+		if r.ts.After(until) {
+			break
+		}
+		records = append(records, r)
 	}
+	records = append(records, record{ts: until})
+	return w.buildReport(records)
+}
 
-	splits := w.calculateSplits()
-	rpt := Report{
-		Duration: duration,
-		Splits:   splits,
+func (w *Stopwatch) buildReport(records []record) Report {
+	begin, end := records[0].ts, records[len(records)-1].ts
+	pauses, paused := w.calculatePauses(end)
+	var stoppedAt time.Time
+	if records[len(records)-1].key == stop {
+		stoppedAt = end
 	}
-	return rpt, nil
+
+	return Report{
+		Name:           w.Name,
+		StartedAt:      begin,
+		StoppedAt:      stoppedAt,
+		Duration:       w.durationBetween(begin, end),
+		Splits:         w.calculateSplits(records),
+		PausedDuration: paused,
+		Pauses:         pauses,
+		Branches:       w.calculateNamedIntervals(w.branches, end),
+		Intervals:      w.calculateNamedIntervals(w.intervals, end),
+		Children:       w.calculateChildren(end),
+	}
+}
+
+//calculateChildren cuts children off at until, the end of this report, even
+//once they stop later, so a stopped parent reports the same children every
+//time.
+func (w *Stopwatch) calculateChildren(until time.Time) []Report {
+	children := make([]Report, 0, len(w.children))
+	for _, child := range w.children {
+		child.rl.Lock()
+		if child.started() && !child.records[0].ts.After(until) {
+			children = append(children, child.snapshot(until))
+		}
+		child.rl.Unlock()
+	}
+
+	return children
 }
 
-func (w *Stopwatch) calculateSplits() []Split {
-	splits := make([]Split, len(w.keys)-1)
-	var splitStart, splitEnd key
+func (w *Stopwatch) calculateSplits(records []record) []Split {
+	splits := make([]Split, len(records)-1)
 	for i := range splits {
-		splitStart, splitEnd = w.keys[i], w.keys[i+1]
-		splits[i] = w.calculateSplit(splitStart, splitEnd)
+		splits[i] = w.calculateSplit(records[i], records[i+1])
 	}
 
 	return splits
 }
 
-func (w *Stopwatch) calculateSplit(begin, end key) Split {
-	rec := w.records[begin]
-	dur, _ := w.calculateDuration(begin, end)
-	return newSplit(begin.String(), rec.comment, dur)
+func (w *Stopwatch) calculateSplit(begin, end record) Split {
+	dur := w.durationBetween(begin.ts, end.ts)
+	return newSplit(begin.key.String(), begin.comment, begin.ts, end.ts, dur)
 }
 
-func newSplit(splitName string, splitComment string, dur time.Duration) Split {
+func newSplit(splitName string, splitComment string, splitStart, splitEnd time.Time, dur time.Duration) Split {
 	return Split{
 		Name:     splitName,
 		Comment:  splitComment,
+		Start:    splitStart,
+		End:      splitEnd,
 		Duration: dur,
 	}
 }
 func (w *Stopwatch) calculateDuration(from, to key) (time.Duration, error) {
-	fromRecord, exists := w.records[from]
+	fromRecord, exists := w.findRecord(from)
 	if !exists {
 		return time.Duration(0), NewNonExistentKeyErr(w, from)
 	}
 
-	toRecord, exists := w.records[to]
+	toRecord, exists := w.findRecord(to)
 	if !exists {
 		return time.Duration(0), NewNonExistentKeyErr(w, to)
 	}
 
-	dur := math.Abs(float64(fromRecord.ts - toRecord.ts))
-	return time.Duration(dur), nil
+	return w.durationBetween(fromRecord.ts, toRecord.ts), nil
+}
+
+//findRecord returns the first record for k; repeated laps keep their own
+//records but are addressed by their first occurrence.
+func (w *Stopwatch) findRecord(k key) (record, bool) {
+	for _, rec := range w.records {
+		if rec.key == k {
+			return rec, true
+		}
+	}
+
+	return record{}, false
 }
 
+func (w *Stopwatch) durationBetween(begin, end time.Time) time.Duration {
+	if end.Before(begin) {
+		begin, end = end, begin
+	}
+	return end.Sub(begin) - w.pausedBetween(begin, end)
+}
+
+func (w *Stopwatch) pausedBetween(begin, end time.Time) time.Duration {
+	var paused time.Duration
+	for _, p := range w.pauses {
+		pauseEnd := p.end
+		if pauseEnd.IsZero() {
+			pauseEnd = end
+		}
+
+		from, to := p.begin, pauseEnd
+		if from.Before(begin) {
+			from = begin
+		}
+		if to.After(end) {
+			to = end
+		}
+		if to.After(from) {
+			paused += to.Sub(from)
+		}
+	}
+
+	return paused
+}
+
+func (w *Stopwatch) calculatePauses(now time.Time) ([]Interval, time.Duration) {
+	pauses := make([]Interval, 0, len(w.pauses))
+	var total time.Duration
+	for _, p := range w.pauses {
+		if p.begin.After(now) {
+			continue
+		}
+		pauseEnd := p.end
+		if pauseEnd.IsZero() || pauseEnd.After(now) {
+			pauseEnd = now
+		}
+
+		pauses = append(pauses, Interval{
+			Name:     pause.String(),
+			Start:    p.begin,
+			End:      pauseEnd,
+			Duration: pauseEnd.Sub(p.begin),
+		})
+		total += pauseEnd.Sub(p.begin)
+	}
+
+	return pauses, total
+}
+
+//StoppedAt is zero for a Snapshot of a running stopwatch; its last split ends
+//at the time the snapshot was taken.
 type Report struct {
-	Duration time.Duration
-	Splits   []Split
+	Name           string
+	StartedAt      time.Time
+	StoppedAt      time.Time
+	Duration       time.Duration
+	Splits         []Split
+	PausedDuration time.Duration
+	Pauses         []Interval
+	Branches       []Interval
+	Intervals      []Interval
+	Children       []Report
 }
 
-func newRecord(comment string) record {
+func (r Report) Summary() []SplitSummary {
+	summaries := make([]SplitSummary, 0)
+	positions := make(map[string]int)
+	for _, split := range r.Splits {
+		pos, exists := positions[split.Name]
+		if !exists {
+			pos = len(summaries)
+			positions[split.Name] = pos
+			summaries = append(summaries, SplitSummary{
+				Name: split.Name,
+				Min:  split.Duration,
+				Max:  split.Duration,
+			})
+		}
+
+		sum := &summaries[pos]
+		sum.Count++
+		sum.Total += split.Duration
+		if split.Duration < sum.Min {
+			sum.Min = split.Duration
+		}
+		if split.Duration > sum.Max {
+			sum.Max = split.Duration
+		}
+	}
+
+	for i := range summaries {
+		summaries[i].Mean = summaries[i].Total / time.Duration(summaries[i].Count)
+	}
+
+	return summaries
+}
+
+func newRecord(k key, ts time.Time, comment string) record {
 	return record{
-		ts:      time.Now().UTC().UnixNano(),
+		key:     k,
+		ts:      ts,
 		comment: comment,
 	}
 }
@@ -198,25 +565,37 @@ func newKey(keyName string) key {
 	return key(keyName)
 }
 
-func (w *Stopwatch) setRunning(running bool) {
-	w.rl.Lock()
-	defer w.rl.Unlock()
-	w.running = running
+func (w *Stopwatch) started() bool {
+	return len(w.records) > 0 && w.records[0].key == start
 }
 
-func (w *Stopwatch) started() bool {
-	return len(w.keys) > 0 && w.keys[0] == start
+func (w *Stopwatch) paused() bool {
+	lastIdx := len(w.pauses) - 1
+	return len(w.pauses) > 0 && w.pauses[lastIdx].end.IsZero()
 }
 
 func (w *Stopwatch) stopped() bool {
-	lastIdx := len(w.keys) - 1
-	return len(w.keys) > 0 && w.keys[lastIdx] == stop
+	lastIdx := len(w.records) - 1
+	return len(w.records) > 0 && w.records[lastIdx].key == stop
 }
 
 
 //Context Stopwatch Handling
-func CtxNew(ctx context.Context, name string, logger Logger) context.Context {
-	return context.WithValue(ctx, ctxStopwatch, New(name, logger))
+func CtxNew(ctx context.Context, name string, logger Logger, clock Clock) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return context.WithValue(ctx, ctxStopwatch, New(name, logger, clock))
+}
+
+func CtxChild(ctx context.Context, name string) (context.Context, error) {
+	w, err := getStopwatchFromCtx(ctx)
+	if err != nil {
+		return ctx, err
+	}
+
+	return context.WithValue(ctx, ctxStopwatch, w.Child(name)), nil
 }
 
 func CtxStart(ctx context.Context) error {
@@ -246,6 +625,24 @@ func CtxLap(ctx context.Context, lapKey, lapComment string) error {
 	return w.Lap(lapKey, lapComment)
 }
 
+func CtxPause(ctx context.Context) error {
+	w, err := getStopwatchFromCtx(ctx)
+	if err != nil {
+		return err
+	}
+
+	return w.Pause()
+}
+
+func CtxResume(ctx context.Context) error {
+	w, err := getStopwatchFromCtx(ctx)
+	if err != nil {
+		return err
+	}
+
+	return w.Resume()
+}
+
 func CtxReport(ctx context.Context) (Report, error) {
 	w, err := getStopwatchFromCtx(ctx)
 	if err != nil {
@@ -255,6 +652,24 @@ func CtxReport(ctx context.Context) (Report, error) {
 	return w.Report()
 }
 
+func CtxElapsed(ctx context.Context) (time.Duration, error) {
+	w, err := getStopwatchFromCtx(ctx)
+	if err != nil {
+		return time.Duration(0), err
+	}
+
+	return w.Elapsed()
+}
+
+func CtxSnapshot(ctx context.Context) (Report, error) {
+	w, err := getStopwatchFromCtx(ctx)
+	if err != nil {
+		return Report{}, err
+	}
+
+	return w.Snapshot()
+}
+
 func getStopwatchFromCtx(ctx context.Context) (*Stopwatch, error) {
 	wi := ctx.Value(ctxStopwatch)
 	if wi == nil {
@@ -2,8 +2,10 @@ package stopwatch
 
 import (
 	"context"
+	"fmt"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
+	"sync"
 	"testing"
 	"time"
 )
@@ -18,11 +20,50 @@ func EqualLog(t *testing.T, expected testLog, actual testLog) {
 
 func AssertEqualRecord(t *testing.T, expected record, actual record) {
 	assert.Equal(t, expected.comment, actual.comment)
-	assert.InDelta(t, expected.ts, actual.ts, nanoTsDelta)
+	assert.InDelta(t, expected.ts.UnixNano(), actual.ts.UnixNano(), nanoTsDelta)
+}
+
+var fixtureStart = time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+
+//fixtureReports runs a "handler" stopwatch n times, back to back, on a
+//ManualClock from fixtureStart. Run k (from 0) laps start 1ms, db "users
+//table" (7+k)ms with a 1ms pause inside, and render 2ms, inside a "request"
+//interval. A "db" child covers the db split with laps start 2ms and
+//query;select (6+k)ms.
+func fixtureReports(n int) []Report {
+	clock := NewManualClock(fixtureStart)
+	w := New("handler", nil, clock)
+	reports := make([]Report, 0, n)
+	for k := 0; k < n; k++ {
+		w.Restart()
+		child := w.Child("db")
+		_ = w.Begin("request")
+		clock.Advance(time.Millisecond)
+		_ = w.Lap("db", "users table")
+		_ = child.Start()
+		clock.Advance(2 * time.Millisecond)
+		_ = child.Lap("query;select", "")
+		clock.Advance(2 * time.Millisecond)
+		_ = w.Pause()
+		clock.Advance(time.Millisecond)
+		_ = w.Resume()
+		clock.Advance(time.Duration(3+k) * time.Millisecond)
+		_ = child.Stop()
+		_ = w.Lap("render", "")
+		clock.Advance(2 * time.Millisecond)
+		_ = w.End("request")
+		_ = w.Stop()
+		rpt, _ := w.Report()
+		reports = append(reports, rpt)
+	}
+
+	return reports
 }
 
 type testLogger struct {
 	logs []testLog
+
+	ll sync.Mutex
 }
 
 type testLog struct {
@@ -32,6 +73,8 @@ type testLog struct {
 }
 
 func (l *testLogger) Log(ts int64, key string, comment string) {
+	l.ll.Lock()
+	defer l.ll.Unlock()
 	l.logs = append(l.logs, testLog{
 		ts:      ts,
 		key:     key,
@@ -56,26 +99,35 @@ func (cs *creationSuite) SetupTest() {
 }
 
 func (cs *creationSuite) TestNew_WithNilLogger() {
-	w := New(cs.stopwatchName, nil)
+	w := New(cs.stopwatchName, nil, nil)
 	assert.NotNil(cs.T(), w)
 	assert.IsType(cs.T(), &Stopwatch{}, w)
 
 	assert.Equal(cs.T(), cs.stopwatchName, w.Name)
 	assert.Equal(cs.T(), &nopLogger{}, w.Logger)
-	assert.NotNil(cs.T(), w.keys)
+	assert.Equal(cs.T(), &systemClock{}, w.Clock)
 	assert.NotNil(cs.T(), w.records)
 	assert.False(cs.T(), w.running)
+	assert.Equal(cs.T(), DefaultHistorySize, w.HistorySize)
+	assert.NotNil(cs.T(), w.history)
 	assert.NotNil(cs.T(), w.rl)
 }
 
 func (cs *creationSuite) TestNew_WithTestLogger() {
-	w := New(cs.stopwatchName, cs.testLogger)
+	w := New(cs.stopwatchName, cs.testLogger, nil)
 	assert.NotNil(cs.T(), w)
 	assert.IsType(cs.T(), &Stopwatch{}, w)
 
 	assert.IsType(cs.T(), &testLogger{}, w.Logger)
 }
 
+func (cs *creationSuite) TestNew_WithManualClock() {
+	clock := NewManualClock(time.Now())
+	w := New(cs.stopwatchName, nil, clock)
+	assert.NotNil(cs.T(), w)
+	assert.Equal(cs.T(), clock, w.Clock)
+}
+
 //Start
 //DoubleStart
 //StartAfterStop
@@ -92,22 +144,21 @@ type startSuite struct {
 
 func (ss *startSuite) SetupTest() {
 	ss.logger = &testLogger{}
-	ss.w = New("test", ss.logger)
+	ss.w = New("test", ss.logger, nil)
 }
 
 func (ss *startSuite) TestStart_Success() {
-	expectedStartTime := time.Now().UnixNano()
+	expectedStartTime := time.Now()
 	err := ss.w.Start()
 	assert.Nil(ss.T(), err)
 	assert.True(ss.T(), ss.w.Running())
 
 	//confirm start adds appropriate key
-	assert.Equal(ss.T(), 1, len(ss.w.keys))
-	assert.Equal(ss.T(), start, ss.w.keys[0])
 	assert.Equal(ss.T(), 1, len(ss.w.records))
+	assert.Equal(ss.T(), start, ss.w.records[0].key)
 
 	//confirm start adds appropriate record
-	startRecord, exists := ss.w.records[start]
+	startRecord, exists := ss.w.findRecord(start)
 	assert.True(ss.T(), exists)
 	assert.NotZero(ss.T(), startRecord)
 	expectedRecord := record{
@@ -120,7 +171,7 @@ func (ss *startSuite) TestStart_Success() {
 	expectedNumOfLogs := 1
 	assert.Equal(ss.T(), expectedNumOfLogs, len(ss.logger.logs))
 	expectedLog := testLog{
-		ts:      expectedStartTime,
+		ts:      expectedStartTime.UnixNano(),
 		key:     string(start),
 		comment: "",
 	}
@@ -162,31 +213,30 @@ type stopSuite struct {
 
 func (st *stopSuite) SetupTest() {
 	st.testLogger = &testLogger{}
-	st.w = New("test", st.testLogger)
+	st.w = New("test", st.testLogger, nil)
 }
 
 func (st *stopSuite) TestStop_Success() {
 	err := st.w.Start()
 	assert.Nil(st.T(), err)
-	expectedStopTs := time.Now().UTC().UnixNano()
+	expectedStopTs := time.Now()
 	err = st.w.Stop()
 	assert.Nil(st.T(), err)
-	expectedLenOfKeys := 2
-	assert.Equal(st.T(), expectedLenOfKeys, len(st.w.keys))
-	assert.Equal(st.T(), stop, st.w.keys[1])
 	expectedLenOfRecords := 2
 	assert.Equal(st.T(), expectedLenOfRecords, len(st.w.records))
+	assert.Equal(st.T(), stop, st.w.records[1].key)
 	expectedRecord := record{
+		key:     stop,
 		ts:      expectedStopTs,
 		comment: "",
 	}
-	stopRecord := st.w.records[stop]
+	stopRecord := st.w.records[1]
 	AssertEqualRecord(st.T(), expectedRecord, stopRecord)
 
 	expectedNumOfLogs := 2
 	assert.Equal(st.T(), expectedNumOfLogs, len(st.testLogger.logs))
 	expectedLog := testLog{
-		ts:      expectedStopTs,
+		ts:      expectedStopTs.UnixNano(),
 		key:     string(stop),
 		comment: "",
 	}
@@ -234,7 +284,7 @@ type lapSuite struct {
 
 func (ls *lapSuite) SetupTest() {
 	ls.logger = &testLogger{}
-	ls.w = New("test", ls.logger)
+	ls.w = New("test", ls.logger, nil)
 	ls.key = "key"
 	ls.comment = "lap-comment"
 }
@@ -248,29 +298,54 @@ func (ls *lapSuite) TestLap_Success() {
 	assert.Nil(ls.T(), err)
 
 	//internal function check
-	expectedNumOfKeys := 2
-	assert.Equal(ls.T(), expectedNumOfKeys, len(ls.w.keys))
-	assert.Equal(ls.T(), ls.w.keys[1], newKey(ls.key))
-	assert.Equal(ls.T(), expectedNumOfKeys, len(ls.w.records))
+	expectedNumOfRecords := 2
+	assert.Equal(ls.T(), expectedNumOfRecords, len(ls.w.records))
+	assert.Equal(ls.T(), ls.w.records[1].key, newKey(ls.key))
 
 	//integrity check
-	lapRecord, exists := ls.w.records[newKey(ls.key)]
+	lapRecord, exists := ls.w.findRecord(newKey(ls.key))
 	assert.True(ls.T(), exists)
 	assert.NotZero(ls.T(), lapRecord)
-	assert.InDelta(ls.T(), expectedTs, lapRecord.ts, nanoTsDelta)
+	assert.InDelta(ls.T(), expectedTs, lapRecord.ts.UnixNano(), nanoTsDelta)
 	assert.Equal(ls.T(), ls.comment, lapRecord.comment)
 
 	//log check
 	expectedNumOfLogs := 2
 	assert.Equal(ls.T(), expectedNumOfLogs, len(ls.logger.logs))
 	expectedLapLog := testLog{
-		ts:      lapRecord.ts,
+		ts:      lapRecord.ts.UnixNano(),
 		key:     ls.key,
 		comment: lapRecord.comment,
 	}
 	assert.Equal(ls.T(), expectedLapLog, ls.logger.logs[1])
 }
 
+func (ls *lapSuite) TestLap_Success_RepeatedKey() {
+	clock := NewManualClock(time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC))
+	w := New("test", ls.logger, clock)
+	_ = w.Start()
+	for i := 1; i <= 3; i++ {
+		clock.Advance(time.Duration(i) * time.Second)
+		err := w.Lap("batch", "")
+		assert.Nil(ls.T(), err)
+	}
+	clock.Advance(time.Second)
+	_ = w.Stop()
+
+	expectedNumOfRecords := 5
+	assert.Equal(ls.T(), expectedNumOfRecords, len(w.records))
+
+	rpt, err := w.Report()
+	assert.Nil(ls.T(), err)
+	expectedDurations := []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, time.Second}
+	assert.Equal(ls.T(), len(expectedDurations), len(rpt.Splits))
+	for i, dur := range expectedDurations {
+		assert.Equal(ls.T(), dur, rpt.Splits[i].Duration)
+	}
+	assert.Equal(ls.T(), "batch", rpt.Splits[1].Name)
+	assert.Equal(ls.T(), "batch", rpt.Splits[3].Name)
+}
+
 func (ls *lapSuite) TestLap_Error_NotStarted() {
 	err := ls.w.Lap(ls.key, ls.comment)
 	assert.NotNil(ls.T(), err)
@@ -288,15 +363,157 @@ func (ls *lapSuite) TestLap_Error_AlreadyStopped() {
 	assert.Equal(ls.T(), NewAlreadyStoppedErr(ls.w).Error(), err.Error())
 }
 
+//Success
+// - Splits exclude paused time
+// - Stop while paused
+//Error
+// - NotStarted
+// - AlreadyStopped
+// - AlreadyPaused
+// - NotPaused
+func TestPause(t *testing.T) {
+	ps := new(pauseSuite)
+	suite.Run(t, ps)
+}
+
+type pauseSuite struct {
+	w      *Stopwatch
+	clock  *ManualClock
+	logger *testLogger
+	suite.Suite
+}
+
+func (ps *pauseSuite) SetupTest() {
+	ps.logger = &testLogger{}
+	ps.clock = NewManualClock(time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC))
+	ps.w = New("test", ps.logger, ps.clock)
+}
+
+func (ps *pauseSuite) TestPause_Success() {
+	_ = ps.w.Start()
+	ps.clock.Advance(time.Second)
+	_ = ps.w.Lap("work", "")
+	ps.clock.Advance(time.Second)
+
+	pausedAt := ps.clock.Now()
+	err := ps.w.Pause()
+	assert.Nil(ps.T(), err)
+	assert.False(ps.T(), ps.w.Running())
+	ps.clock.Advance(time.Minute)
+
+	resumedAt := ps.clock.Now()
+	err = ps.w.Resume()
+	assert.Nil(ps.T(), err)
+	assert.True(ps.T(), ps.w.Running())
+	ps.clock.Advance(time.Second)
+	_ = ps.w.Stop()
+
+	rpt, err := ps.w.Report()
+	assert.Nil(ps.T(), err)
+	assert.Equal(ps.T(), 3*time.Second, rpt.Duration)
+	assert.Equal(ps.T(), time.Minute, rpt.PausedDuration)
+	expectedPauses := []Interval{
+		{Name: "pause", Start: pausedAt, End: resumedAt, Duration: time.Minute},
+	}
+	assert.Equal(ps.T(), expectedPauses, rpt.Pauses)
+
+	assert.Equal(ps.T(), 2, len(rpt.Splits))
+	assert.Equal(ps.T(), time.Second, rpt.Splits[0].Duration)
+	assert.Equal(ps.T(), 2*time.Second, rpt.Splits[1].Duration)
+
+	expectedNumOfLogs := 5
+	assert.Equal(ps.T(), expectedNumOfLogs, len(ps.logger.logs))
+	assert.Equal(ps.T(), pause.String(), ps.logger.logs[2].key)
+	assert.Equal(ps.T(), resume.String(), ps.logger.logs[3].key)
+}
+
+func (ps *pauseSuite) TestPause_Success_StopWhilePaused() {
+	_ = ps.w.Start()
+	ps.clock.Advance(time.Second)
+	_ = ps.w.Pause()
+	ps.clock.Advance(time.Minute)
+	err := ps.w.Stop()
+	assert.Nil(ps.T(), err)
+
+	rpt, err := ps.w.Report()
+	assert.Nil(ps.T(), err)
+	assert.Equal(ps.T(), time.Second, rpt.Duration)
+	assert.Equal(ps.T(), time.Minute, rpt.PausedDuration)
+	assert.Equal(ps.T(), 1, len(rpt.Pauses))
+	assert.Equal(ps.T(), ps.clock.Now(), rpt.Pauses[0].End)
+}
+
+func (ps *pauseSuite) TestPause_Error_NotStarted() {
+	err := ps.w.Pause()
+	assert.NotNil(ps.T(), err)
+	assert.IsType(ps.T(), &StopwatchErr{}, err)
+	assert.Equal(ps.T(), NewNotStartedErr(ps.w).Error(), err.Error())
+
+	err = ps.w.Resume()
+	assert.NotNil(ps.T(), err)
+	assert.Equal(ps.T(), NewNotStartedErr(ps.w).Error(), err.Error())
+}
+
+func (ps *pauseSuite) TestPause_Error_AlreadyStopped() {
+	_ = ps.w.Start()
+	_ = ps.w.Stop()
+	err := ps.w.Pause()
+	assert.NotNil(ps.T(), err)
+	assert.Equal(ps.T(), NewAlreadyStoppedErr(ps.w).Error(), err.Error())
+
+	err = ps.w.Resume()
+	assert.NotNil(ps.T(), err)
+	assert.Equal(ps.T(), NewAlreadyStoppedErr(ps.w).Error(), err.Error())
+}
+
+func (ps *pauseSuite) TestPause_Error_AlreadyPaused() {
+	_ = ps.w.Start()
+	err := ps.w.Pause()
+	assert.Nil(ps.T(), err)
+	err = ps.w.Pause()
+	assert.NotNil(ps.T(), err)
+	assert.IsType(ps.T(), &StopwatchErr{}, err)
+	assert.Equal(ps.T(), NewAlreadyPausedErr(ps.w).Error(), err.Error())
+}
+
+func (ps *pauseSuite) TestResume_Error_NotPaused() {
+	_ = ps.w.Start()
+	err := ps.w.Resume()
+	assert.NotNil(ps.T(), err)
+	assert.IsType(ps.T(), &StopwatchErr{}, err)
+	assert.Equal(ps.T(), NewNotPausedErr(ps.w).Error(), err.Error())
+}
+
 func TestNewRecord(t *testing.T) {
 	comment := "test-comment"
-	r := newRecord(comment)
-	now := time.Now().UnixNano()
+	now := time.Now()
+	r := newRecord(newKey("test"), now, comment)
 	assert.NotZero(t, r)
-	assert.InDelta(t, now, r.ts, nanoTsDelta)
+	assert.Equal(t, newKey("test"), r.key)
+	assert.Equal(t, now, r.ts)
 	assert.Equal(t, comment, r.comment)
 }
 
+func TestReportSummary(t *testing.T) {
+	rpt := Report{
+		Splits: []Split{
+			{Name: "start", Duration: time.Second},
+			{Name: "batch", Duration: 2 * time.Second},
+			{Name: "batch", Duration: 4 * time.Second},
+			{Name: "flush", Duration: time.Second},
+			{Name: "batch", Duration: 3 * time.Second},
+		},
+	}
+
+	expectedSummaries := []SplitSummary{
+		{Name: "start", Count: 1, Total: time.Second, Min: time.Second, Max: time.Second, Mean: time.Second},
+		{Name: "batch", Count: 3, Total: 9 * time.Second, Min: 2 * time.Second, Max: 4 * time.Second, Mean: 3 * time.Second},
+		{Name: "flush", Count: 1, Total: time.Second, Min: time.Second, Max: time.Second, Mean: time.Second},
+	}
+	assert.Equal(t, expectedSummaries, rpt.Summary())
+	assert.Empty(t, Report{}.Summary())
+}
+
 //Success
 // - No records
 // - Some Records
@@ -317,7 +534,7 @@ type fullReportSuite struct {
 
 func (frs *fullReportSuite) SetupTest() {
 	frs.logger = &testLogger{}
-	frs.w = New("test-stopwatch", frs.logger)
+	frs.w = New("test-stopwatch", frs.logger, nil)
 }
 
 func (frs *fullReportSuite) TestFullReport_Success() {
@@ -339,10 +556,10 @@ func (frs *fullReportSuite) TestFullReport_Success() {
 	assert.Nil(frs.T(), err)
 	assert.Equal(frs.T(), expectedDuration, rpt.Duration)
 	assert.NotNil(frs.T(), rpt.Splits)
-	expectedNumberOfSplits := len(frs.w.keys) - 1
+	expectedNumberOfSplits := len(frs.w.records) - 1
 	assert.Equal(frs.T(), expectedNumberOfSplits, len(rpt.Splits))
 
-	expectedSplits := frs.w.calculateSplits()
+	expectedSplits := frs.w.calculateSplits(frs.w.records)
 	assert.Equal(frs.T(), expectedSplits, rpt.Splits)
 }
 
@@ -366,6 +583,375 @@ func (frs *fullReportSuite) TestFullReport_Error_NotStopped() {
 	assert.Equal(frs.T(), expectedErr.Error(), err.Error())
 }
 
+func (frs *fullReportSuite) TestFullReport_Success_ManualClock() {
+	begin := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) time.Time {
+		return begin.Add(d)
+	}
+	clock := NewManualClock(begin)
+	w := New("manual", frs.logger, clock)
+	_ = w.Start()
+	clock.Advance(10 * time.Millisecond)
+	_ = w.Lap("first", "woof")
+	clock.Advance(250 * time.Millisecond)
+	_ = w.Lap("second", "meow")
+	clock.Advance(time.Second)
+	_ = w.Stop()
+
+	rpt, err := w.Report()
+	assert.Nil(frs.T(), err)
+	assert.Equal(frs.T(), 1260*time.Millisecond, rpt.Duration)
+	assert.Equal(frs.T(), begin, rpt.StartedAt)
+	assert.Equal(frs.T(), at(1260*time.Millisecond), rpt.StoppedAt)
+	expectedSplits := []Split{
+		{Name: "start", Comment: "", Start: begin, End: at(10 * time.Millisecond), Duration: 10 * time.Millisecond},
+		{Name: "first", Comment: "woof", Start: at(10 * time.Millisecond), End: at(260 * time.Millisecond), Duration: 250 * time.Millisecond},
+		{Name: "second", Comment: "meow", Start: at(260 * time.Millisecond), End: at(1260 * time.Millisecond), Duration: time.Second},
+	}
+	assert.Equal(frs.T(), expectedSplits, rpt.Splits)
+}
+
+//Success
+// - Running
+// - Paused
+// - Stopped
+//Error
+// - NotStarted
+func TestStopwatch_Snapshot(t *testing.T) {
+	ss := new(snapshotSuite)
+	suite.Run(t, ss)
+}
+
+type snapshotSuite struct {
+	w     *Stopwatch
+	clock *ManualClock
+	suite.Suite
+}
+
+func (ss *snapshotSuite) SetupTest() {
+	ss.clock = NewManualClock(time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC))
+	ss.w = New("test", nil, ss.clock)
+}
+
+func (ss *snapshotSuite) TestSnapshot_Success_Running() {
+	_ = ss.w.Start()
+	ss.clock.Advance(time.Second)
+	_ = ss.w.Lap("load", "reading input")
+	ss.clock.Advance(3 * time.Second)
+
+	elapsed, err := ss.w.Elapsed()
+	assert.Nil(ss.T(), err)
+	assert.Equal(ss.T(), 4*time.Second, elapsed)
+
+	rpt, err := ss.w.Snapshot()
+	assert.Nil(ss.T(), err)
+	assert.Equal(ss.T(), 4*time.Second, rpt.Duration)
+	begin := ss.clock.Now().Add(-4 * time.Second)
+	assert.Equal(ss.T(), begin, rpt.StartedAt)
+	assert.True(ss.T(), rpt.StoppedAt.IsZero())
+	expectedSplits := []Split{
+		{Name: "start", Comment: "", Start: begin, End: begin.Add(time.Second), Duration: time.Second},
+		{Name: "load", Comment: "reading input", Start: begin.Add(time.Second), End: ss.clock.Now(), Duration: 3 * time.Second},
+	}
+	assert.Equal(ss.T(), expectedSplits, rpt.Splits)
+
+	//snapshot must not record anything
+	assert.True(ss.T(), ss.w.Running())
+	assert.Equal(ss.T(), 2, len(ss.w.records))
+}
+
+func (ss *snapshotSuite) TestSnapshot_Success_Paused() {
+	_ = ss.w.Start()
+	ss.clock.Advance(time.Second)
+	_ = ss.w.Pause()
+	ss.clock.Advance(time.Minute)
+
+	elapsed, err := ss.w.Elapsed()
+	assert.Nil(ss.T(), err)
+	assert.Equal(ss.T(), time.Second, elapsed)
+
+	rpt, err := ss.w.Snapshot()
+	assert.Nil(ss.T(), err)
+	assert.Equal(ss.T(), time.Second, rpt.Duration)
+	assert.Equal(ss.T(), time.Minute, rpt.PausedDuration)
+	assert.Equal(ss.T(), ss.clock.Now(), rpt.Pauses[0].End)
+	assert.True(ss.T(), ss.w.paused())
+}
+
+func (ss *snapshotSuite) TestSnapshot_Success_Stopped() {
+	_ = ss.w.Start()
+	ss.clock.Advance(time.Second)
+	_ = ss.w.Stop()
+	ss.clock.Advance(time.Minute)
+
+	elapsed, err := ss.w.Elapsed()
+	assert.Nil(ss.T(), err)
+	assert.Equal(ss.T(), time.Second, elapsed)
+
+	snapshot, err := ss.w.Snapshot()
+	assert.Nil(ss.T(), err)
+	rpt, err := ss.w.Report()
+	assert.Nil(ss.T(), err)
+	assert.Equal(ss.T(), rpt, snapshot)
+}
+
+func (ss *snapshotSuite) TestSnapshot_Error_NotStarted() {
+	elapsed, err := ss.w.Elapsed()
+	assert.Zero(ss.T(), elapsed)
+	assert.NotNil(ss.T(), err)
+	assert.Equal(ss.T(), NewNotStartedErr(ss.w).Error(), err.Error())
+
+	rpt, err := ss.w.Snapshot()
+	assert.Zero(ss.T(), rpt)
+	assert.NotNil(ss.T(), err)
+	assert.IsType(ss.T(), &StopwatchErr{}, err)
+	assert.Equal(ss.T(), NewNotStartedErr(ss.w).Error(), err.Error())
+}
+
+//Success
+// - Nested report
+// - Running child is snapshotted
+// - Running child is frozen at the parent's stop
+// - Unstarted child is skipped
+func TestStopwatch_Child(t *testing.T) {
+	cs := new(childSuite)
+	suite.Run(t, cs)
+}
+
+type childSuite struct {
+	w     *Stopwatch
+	clock *ManualClock
+	suite.Suite
+}
+
+func (cs *childSuite) SetupTest() {
+	cs.clock = NewManualClock(time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC))
+	cs.w = New("handler", &testLogger{}, cs.clock)
+	cs.w.KeyPolicy = DottedLowercaseKeys
+}
+
+func (cs *childSuite) TestChild_Success() {
+	child := cs.w.Child("db")
+	assert.Equal(cs.T(), "db", child.Name)
+	assert.Equal(cs.T(), cs.w, child.Parent())
+	assert.Nil(cs.T(), cs.w.Parent())
+	assert.Equal(cs.T(), cs.w.Logger, child.Logger)
+	assert.Equal(cs.T(), cs.w.Clock, child.Clock)
+	assert.NotNil(cs.T(), child.KeyPolicy)
+
+	_ = cs.w.Start()
+	cs.clock.Advance(time.Millisecond)
+	_ = cs.w.Lap("db", "")
+	_ = child.Start()
+	cs.clock.Advance(2 * time.Millisecond)
+	_ = child.Lap("db.connect", "")
+	cs.clock.Advance(5 * time.Millisecond)
+	_ = child.Stop()
+	_ = cs.w.Lap("render", "")
+	cs.clock.Advance(time.Millisecond)
+	_ = cs.w.Stop()
+
+	rpt, err := cs.w.Report()
+	assert.Nil(cs.T(), err)
+	assert.Equal(cs.T(), "handler", rpt.Name)
+	assert.Equal(cs.T(), 3, len(rpt.Splits))
+	assert.Equal(cs.T(), 7*time.Millisecond, rpt.Splits[1].Duration)
+
+	assert.Equal(cs.T(), 1, len(rpt.Children))
+	childRpt := rpt.Children[0]
+	assert.Equal(cs.T(), "db", childRpt.Name)
+	assert.Equal(cs.T(), 7*time.Millisecond, childRpt.Duration)
+	assert.Equal(cs.T(), rpt.Splits[1].Start, childRpt.StartedAt)
+	assert.Equal(cs.T(), rpt.Splits[1].End, childRpt.StoppedAt)
+	assert.Equal(cs.T(), 2, len(childRpt.Splits))
+	assert.Equal(cs.T(), "start", childRpt.Splits[0].Name)
+	assert.Equal(cs.T(), 2*time.Millisecond, childRpt.Splits[0].Duration)
+	assert.Equal(cs.T(), "db.connect", childRpt.Splits[1].Name)
+	assert.Equal(cs.T(), 5*time.Millisecond, childRpt.Splits[1].Duration)
+	assert.Empty(cs.T(), childRpt.Children)
+}
+
+func (cs *childSuite) TestChild_Success_RunningChild() {
+	child := cs.w.Child("db")
+	grandchild := child.Child("db.pool")
+	_ = cs.w.Start()
+	_ = child.Start()
+	_ = grandchild.Start()
+	cs.clock.Advance(time.Second)
+	_ = cs.w.Stop()
+
+	rpt, err := cs.w.Report()
+	assert.Nil(cs.T(), err)
+	assert.Equal(cs.T(), 1, len(rpt.Children))
+	assert.Equal(cs.T(), time.Second, rpt.Children[0].Duration)
+	assert.Equal(cs.T(), 1, len(rpt.Children[0].Children))
+	assert.Equal(cs.T(), "db.pool", rpt.Children[0].Children[0].Name)
+	assert.True(cs.T(), child.Running())
+}
+
+func (cs *childSuite) TestChild_Success_RunningChildFrozen() {
+	child := cs.w.Child("db")
+	_ = cs.w.Start()
+	_ = child.Start()
+	cs.clock.Advance(time.Second)
+	_ = cs.w.Stop()
+
+	first, err := cs.w.Report()
+	assert.Nil(cs.T(), err)
+
+	cs.clock.Advance(time.Hour)
+	_ = child.Lap("db.late", "")
+
+	second, err := cs.w.Report()
+	assert.Nil(cs.T(), err)
+	assert.Equal(cs.T(), first, second)
+	assert.Equal(cs.T(), time.Second, second.Children[0].Duration)
+	assert.Equal(cs.T(), second.StoppedAt, second.Children[0].Splits[0].End)
+	assert.Equal(cs.T(), 1, len(second.Children[0].Splits))
+
+	//stopping the child afterwards changes nothing either
+	_ = child.Pause()
+	cs.clock.Advance(time.Minute)
+	_ = child.Resume()
+	_ = child.Begin("db.flush")
+	cs.clock.Advance(time.Hour)
+	_ = child.End("db.flush")
+	_ = child.Stop()
+
+	third, err := cs.w.Report()
+	assert.Nil(cs.T(), err)
+	assert.Equal(cs.T(), first, third)
+	assert.True(cs.T(), third.Children[0].StoppedAt.IsZero())
+}
+
+func (cs *childSuite) TestChild_Success_UnstartedChild() {
+	_ = cs.w.Child("db")
+	_ = cs.w.Start()
+	_ = cs.w.Stop()
+
+	rpt, err := cs.w.Report()
+	assert.Nil(cs.T(), err)
+	assert.Empty(cs.T(), rpt.Children)
+}
+
+//Success
+// - Reset stopped watch
+// - Reset running watch
+// - Restart
+// - History is bounded
+// - History disabled
+// - Stale branch
+func TestStopwatch_Reset(t *testing.T) {
+	rs := new(resetSuite)
+	suite.Run(t, rs)
+}
+
+type resetSuite struct {
+	w      *Stopwatch
+	clock  *ManualClock
+	logger *testLogger
+	suite.Suite
+}
+
+func (rs *resetSuite) SetupTest() {
+	rs.logger = &testLogger{}
+	rs.clock = NewManualClock(time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC))
+	rs.w = New("worker", rs.logger, rs.clock)
+}
+
+func (rs *resetSuite) runFor(d time.Duration) {
+	_ = rs.w.Start()
+	_ = rs.w.Pause()
+	_ = rs.w.Resume()
+	_ = rs.w.Begin("job")
+	_ = rs.w.Child("child").Start()
+	rs.clock.Advance(d)
+	_ = rs.w.Lap("job", "")
+	_ = rs.w.Stop()
+}
+
+func (rs *resetSuite) TestReset_Success_Stopped() {
+	rs.runFor(time.Second)
+	expectedRpt, _ := rs.w.Report()
+
+	rs.w.Reset()
+	assert.False(rs.T(), rs.w.Running())
+	assert.False(rs.T(), rs.w.started())
+	assert.Empty(rs.T(), rs.w.records)
+	assert.Empty(rs.T(), rs.w.pauses)
+	assert.Empty(rs.T(), rs.w.intervals)
+	assert.Empty(rs.T(), rs.w.children)
+
+	history := rs.w.History()
+	assert.Equal(rs.T(), []Report{expectedRpt}, history)
+
+	err := rs.w.Start()
+	assert.Nil(rs.T(), err)
+	assert.True(rs.T(), rs.w.Running())
+}
+
+func (rs *resetSuite) TestReset_Success_Running() {
+	_ = rs.w.Start()
+	_ = rs.w.Lap("job", "")
+	rs.w.Reset()
+
+	assert.False(rs.T(), rs.w.Running())
+	assert.Empty(rs.T(), rs.w.records)
+	assert.Empty(rs.T(), rs.w.History())
+
+	_, err := rs.w.Report()
+	assert.NotNil(rs.T(), err)
+	assert.Equal(rs.T(), NewNotStartedErr(rs.w).Error(), err.Error())
+}
+
+func (rs *resetSuite) TestRestart_Success() {
+	rs.runFor(time.Second)
+	rs.w.Restart()
+	assert.True(rs.T(), rs.w.Running())
+	assert.Equal(rs.T(), 1, len(rs.w.records))
+	assert.Equal(rs.T(), start.String(), rs.logger.logs[len(rs.logger.logs)-1].key)
+
+	rs.clock.Advance(2 * time.Second)
+	_ = rs.w.Stop()
+	rs.w.Restart()
+
+	history := rs.w.History()
+	assert.Equal(rs.T(), 2, len(history))
+	assert.Equal(rs.T(), time.Second, history[0].Duration)
+	assert.Equal(rs.T(), 2*time.Second, history[1].Duration)
+}
+
+func (rs *resetSuite) TestHistory_Bounded() {
+	rs.w.HistorySize = 3
+	for i := 1; i <= 5; i++ {
+		rs.runFor(time.Duration(i) * time.Second)
+		rs.w.Reset()
+	}
+
+	history := rs.w.History()
+	assert.Equal(rs.T(), 3, len(history))
+	assert.Equal(rs.T(), 3*time.Second, history[0].Duration)
+	assert.Equal(rs.T(), 5*time.Second, history[2].Duration)
+}
+
+func (rs *resetSuite) TestHistory_Disabled() {
+	rs.w.HistorySize = 0
+	rs.runFor(time.Second)
+	rs.w.Reset()
+	assert.Empty(rs.T(), rs.w.History())
+}
+
+func (rs *resetSuite) TestJoin_Error_StaleBranch() {
+	_ = rs.w.Start()
+	b, _ := rs.w.Fork("users")
+	rs.w.Restart()
+	_, _ = rs.w.Fork("orders")
+
+	err := b.Join()
+	assert.NotNil(rs.T(), err)
+	assert.Equal(rs.T(), NewAlreadyStoppedErr(rs.w).Error(), err.Error())
+}
+
 //Success
 //Error
 // -- NonExistentKey
@@ -381,7 +967,7 @@ type calculateDurationSuite struct {
 }
 
 func (cds *calculateDurationSuite) SetupTest() {
-	cds.w = New("test", nil)
+	cds.w = New("test", nil, nil)
 }
 
 func (cds *calculateDurationSuite) TestCalculateDuration_Success() {
@@ -393,19 +979,11 @@ func (cds *calculateDurationSuite) TestCalculateDuration_Success() {
 	bToStop := 200 * time.Millisecond
 	stopTime := bTime.Add(bToStop)
 
-	cds.w.records = map[key]record{
-		start: record{
-			ts: startTime.UnixNano(),
-		},
-		newKey("a"): record{
-			ts: aTime.UnixNano(),
-		},
-		newKey("b"): record{
-			ts: bTime.UnixNano(),
-		},
-		stop: record{
-			ts: stopTime.UnixNano(),
-		},
+	cds.w.records = []record{
+		newRecord(start, startTime, ""),
+		newRecord(newKey("a"), aTime, ""),
+		newRecord(newKey("b"), bTime, ""),
+		newRecord(stop, stopTime, ""),
 	}
 
 	duration, err := cds.w.calculateDuration(start, stop)
@@ -445,7 +1023,7 @@ func TestCalculateSplits(t *testing.T) {
 		keys[3]: "chirp",
 		keys[4]: "",
 	}
-	w := New("test", &testLogger{})
+	w := New("test", &testLogger{}, nil)
 	_ = w.Start()
 	time.Sleep(500 * time.Nanosecond)
 	expectedDiff := 5000 * time.Nanosecond
@@ -458,8 +1036,8 @@ func TestCalculateSplits(t *testing.T) {
 	}
 	_ = w.Stop()
 
-	splits := w.calculateSplits()
-	assert.Equal(t, len(w.keys)-1, len(splits))
+	splits := w.calculateSplits(w.records)
+	assert.Equal(t, len(w.records)-1, len(splits))
 
 	for i, k := range keys {
 		if i == len(keys)-1 {
@@ -470,12 +1048,144 @@ func TestCalculateSplits(t *testing.T) {
 		expectedSplit := Split{
 			Name:     k.String(),
 			Comment:  keymap[k],
+			Start:    w.records[i].ts,
+			End:      w.records[i+1].ts,
 			Duration: expectedDur,
 		}
 		assert.Equal(t, expectedSplit, splits[i])
 	}
 }
 
+//Success
+// - Concurrent Lap
+// - Concurrent Lap and Report
+// - Concurrent Pause and Resume
+// - Logger calls back into the stopwatch
+func TestStopwatchConcurrency(t *testing.T) {
+	cs := new(concurrencySuite)
+	suite.Run(t, cs)
+}
+
+type concurrencySuite struct {
+	w       *Stopwatch
+	logger  *testLogger
+	workers int
+	laps    int
+	suite.Suite
+}
+
+func (cs *concurrencySuite) SetupTest() {
+	cs.logger = &testLogger{}
+	cs.w = New("test", cs.logger, nil)
+	cs.workers = 8
+	cs.laps = 100
+}
+
+func (cs *concurrencySuite) lapConcurrently() {
+	wg := &sync.WaitGroup{}
+	for i := 0; i < cs.workers; i++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			for j := 0; j < cs.laps; j++ {
+				_ = cs.w.Lap(fmt.Sprintf("worker-%d", worker), "")
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func (cs *concurrencySuite) TestConcurrentLap() {
+	_ = cs.w.Start()
+	cs.lapConcurrently()
+	_ = cs.w.Stop()
+
+	expectedNumOfRecords := cs.workers*cs.laps + 2
+	assert.Equal(cs.T(), expectedNumOfRecords, len(cs.w.records))
+	assert.Equal(cs.T(), expectedNumOfRecords, len(cs.logger.logs))
+	for i := 1; i < len(cs.w.records); i++ {
+		assert.False(cs.T(), cs.w.records[i].ts.Before(cs.w.records[i-1].ts))
+	}
+
+	rpt, err := cs.w.Report()
+	assert.Nil(cs.T(), err)
+	assert.Equal(cs.T(), expectedNumOfRecords-1, len(rpt.Splits))
+	assert.Equal(cs.T(), cs.workers+1, len(rpt.Summary()))
+}
+
+func (cs *concurrencySuite) TestConcurrentLapAndReport() {
+	_ = cs.w.Start()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for cs.w.Running() {
+			_, err := cs.w.Report()
+			if err != nil {
+				assert.Equal(cs.T(), NewNotStoppedErr(cs.w).Error(), err.Error())
+			}
+		}
+	}()
+	cs.lapConcurrently()
+	_ = cs.w.Stop()
+	<-done
+
+	rpt, err := cs.w.Report()
+	assert.Nil(cs.T(), err)
+	assert.Equal(cs.T(), cs.workers*cs.laps+1, len(rpt.Splits))
+}
+
+type reentrantLogger struct {
+	w       *Stopwatch
+	running []bool
+}
+
+func (l *reentrantLogger) Log(_ int64, _, _ string) {
+	l.running = append(l.running, l.w.Running())
+}
+
+func (cs *concurrencySuite) TestLoggerCallsBackIntoStopwatch() {
+	logger := &reentrantLogger{}
+	logger.w = New("test", logger, nil)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = logger.w.Start()
+		_ = logger.w.Lap("lap", "")
+		_ = logger.w.Stop()
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		cs.T().Fatal("Logger calling back into the stopwatch deadlocked")
+	}
+	assert.Equal(cs.T(), []bool{true, true, false}, logger.running)
+}
+
+func (cs *concurrencySuite) TestConcurrentPauseAndResume() {
+	_ = cs.w.Start()
+	wg := &sync.WaitGroup{}
+	for i := 0; i < cs.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < cs.laps; j++ {
+				_ = cs.w.Pause()
+				_ = cs.w.Resume()
+			}
+		}()
+	}
+	wg.Wait()
+	_ = cs.w.Stop()
+
+	rpt, err := cs.w.Report()
+	assert.Nil(cs.T(), err)
+	for _, p := range rpt.Pauses {
+		assert.False(cs.T(), p.End.IsZero())
+	}
+}
+
 type ctxSuite struct {
 	ctx    context.Context
 	logger *testLogger
@@ -503,7 +1213,7 @@ func (cns *ctxNewSuite) SetupTest() {
 }
 
 func (cns *ctxNewSuite) TestCtxNew_Success() {
-	cns.ctx = CtxNew(cns.ctx, cns.name, cns.logger)
+	cns.ctx = CtxNew(cns.ctx, cns.name, cns.logger, nil)
 
 	w := cns.ctx.Value(ctxStopwatch)
 	assert.NotNil(cns.T(), w)
@@ -520,7 +1230,7 @@ func (cns *ctxNewSuite) TestCtxNew_Success() {
 }
 
 func (cns *ctxNewSuite) TestCtxNew_NilCtx() {
-	newCtx := CtxNew(nil, cns.name, cns.logger)
+	newCtx := CtxNew(nil, cns.name, cns.logger, nil)
 	assert.NotNil(cns.T(), newCtx)
 
 	w, err := getStopwatchFromCtx(newCtx)
@@ -546,7 +1256,7 @@ type ctxStartSuite struct {
 func (css *ctxStartSuite) SetupTest() {
 	css.ctxSuite.SetupTest()
 	css.name = "test"
-	css.ctx = CtxNew(css.ctx, css.name, css.logger)
+	css.ctx = CtxNew(css.ctx, css.name, css.logger, nil)
 }
 
 func (css *ctxStartSuite) TestCtxStart_Success() {
@@ -614,7 +1324,7 @@ type ctxStopSuite struct {
 func (cst *ctxStopSuite) SetupTest() {
 	cst.ctxSuite.SetupTest()
 	cst.name = "test"
-	cst.ctx = CtxNew(cst.ctx, cst.name, cst.logger)
+	cst.ctx = CtxNew(cst.ctx, cst.name, cst.logger, nil)
 }
 
 func (cst *ctxStopSuite) TestCtxStop_Success() {
@@ -626,10 +1336,10 @@ func (cst *ctxStopSuite) TestCtxStop_Success() {
 	w, err := getStopwatchFromCtx(cst.ctx)
 	assert.Nil(cst.T(), err)
 
-	expectedLenOfKeys := 2
-	assert.Equal(cst.T(), expectedLenOfKeys, len(w.keys))
-	assert.Equal(cst.T(), w.keys[0], start)
-	assert.Equal(cst.T(), w.keys[1], stop)
+	expectedLenOfRecords := 2
+	assert.Equal(cst.T(), expectedLenOfRecords, len(w.records))
+	assert.Equal(cst.T(), w.records[0].key, start)
+	assert.Equal(cst.T(), w.records[1].key, stop)
 }
 
 func (cst *ctxStopSuite) TestCtxStop_Error_NotStarted() {
@@ -688,22 +1398,21 @@ func (cls *ctxLapSuite) SetupTest() {
 }
 
 func (cls *ctxLapSuite) TestCtxLap_Success() {
-	cls.ctx = CtxNew(cls.ctx, cls.name, cls.logger)
+	cls.ctx = CtxNew(cls.ctx, cls.name, cls.logger, nil)
 	_ = CtxStart(cls.ctx)
-	expectedTs := time.Now().UnixNano()
+	expectedTs := time.Now()
 	err := CtxLap(cls.ctx, cls.key, cls.comment)
 	assert.Nil(cls.T(), err)
 
 	w, err := getStopwatchFromCtx(cls.ctx)
 	assert.Nil(cls.T(), err)
 
-	expectedLenOfKeys := 2
-	assert.Equal(cls.T(), expectedLenOfKeys, len(w.keys))
-	assert.Equal(cls.T(), w.keys[0], start)
-	assert.Equal(cls.T(), w.keys[1], newKey(cls.key))
+	expectedLenOfRecords := 2
+	assert.Equal(cls.T(), expectedLenOfRecords, len(w.records))
+	assert.Equal(cls.T(), w.records[0].key, start)
+	assert.Equal(cls.T(), w.records[1].key, newKey(cls.key))
 
-	assert.Equal(cls.T(), expectedLenOfKeys, len(w.records))
-	l, exists := w.records[newKey(cls.key)]
+	l, exists := w.findRecord(newKey(cls.key))
 	assert.True(cls.T(), exists)
 	expectedEntry := record{
 		ts: expectedTs,
@@ -713,7 +1422,7 @@ func (cls *ctxLapSuite) TestCtxLap_Success() {
 }
 
 func (cls *ctxLapSuite) TestCtxLap_Error_NotStarted() {
-	cls.ctx = CtxNew(cls.ctx, cls.name, cls.logger)
+	cls.ctx = CtxNew(cls.ctx, cls.name, cls.logger, nil)
 	err := CtxLap(cls.ctx, cls.key, cls.comment)
 	assert.NotNil(cls.T(), err)
 
@@ -723,13 +1432,12 @@ func (cls *ctxLapSuite) TestCtxLap_Error_NotStarted() {
 	expectedErr := NewNotStartedErr(w)
 	assert.Equal(cls.T(), expectedErr.Error(), err.Error())
 
-	expectedLenOfKeys := 0
-	assert.Equal(cls.T(), expectedLenOfKeys, len(w.keys))
-	assert.Equal(cls.T(), expectedLenOfKeys, len(w.records))
+	expectedLenOfRecords := 0
+	assert.Equal(cls.T(), expectedLenOfRecords, len(w.records))
 }
 
 func (cls *ctxLapSuite) TestCtxLap_Error_AlreadyStopped() {
-	cls.ctx = CtxNew(cls.ctx, cls.name, cls.logger)
+	cls.ctx = CtxNew(cls.ctx, cls.name, cls.logger, nil)
 	_ = CtxStart(cls.ctx)
 	_ = CtxStop(cls.ctx)
 	err := CtxLap(cls.ctx, cls.key, cls.comment)
@@ -740,9 +1448,8 @@ func (cls *ctxLapSuite) TestCtxLap_Error_AlreadyStopped() {
 	expectedErr := NewAlreadyStoppedErr(w)
 	assert.Equal(cls.T(), expectedErr.Error(), err.Error())
 
-	expectedLenOfKeys := 2
-	assert.Equal(cls.T(), expectedLenOfKeys, len(w.keys))
-	assert.Equal(cls.T(), expectedLenOfKeys, len(w.records))
+	expectedLenOfRecords := 2
+	assert.Equal(cls.T(), expectedLenOfRecords, len(w.records))
 }
 
 func (cls *ctxLapSuite) TestCtxLap_Error_NotFound() {
@@ -754,6 +1461,54 @@ func (cls *ctxLapSuite) TestCtxLap_Error_NotFound() {
 	assert.Equal(cls.T(), expectedErr.Error(), err.Error())
 }
 
+//Success
+//Error
+// - NotFound
+
+func TestCtxPause(t *testing.T) {
+	cps := new(ctxPauseSuite)
+	suite.Run(t, cps)
+}
+
+type ctxPauseSuite struct {
+	name string
+	ctxSuite
+}
+
+func (cps *ctxPauseSuite) SetupTest() {
+	cps.name = "test"
+	cps.ctxSuite.SetupTest()
+}
+
+func (cps *ctxPauseSuite) TestCtxPause_Success() {
+	cps.ctx = CtxNew(cps.ctx, cps.name, cps.logger, nil)
+	_ = CtxStart(cps.ctx)
+
+	err := CtxPause(cps.ctx)
+	assert.Nil(cps.T(), err)
+	w, err := getStopwatchFromCtx(cps.ctx)
+	assert.Nil(cps.T(), err)
+	assert.True(cps.T(), w.paused())
+
+	err = CtxResume(cps.ctx)
+	assert.Nil(cps.T(), err)
+	assert.False(cps.T(), w.paused())
+	assert.Equal(cps.T(), 1, len(w.pauses))
+}
+
+func (cps *ctxPauseSuite) TestCtxPause_Error_NotFound() {
+	ctx := context.Background()
+	expectedErr := NewNotFoundErr()
+
+	err := CtxPause(ctx)
+	assert.NotNil(cps.T(), err)
+	assert.Equal(cps.T(), expectedErr.Error(), err.Error())
+
+	err = CtxResume(ctx)
+	assert.NotNil(cps.T(), err)
+	assert.Equal(cps.T(), expectedErr.Error(), err.Error())
+}
+
 //Success
 //Error
 // - NotStarted
@@ -776,7 +1531,7 @@ func (crs *ctxReportSuite) SetupTest() {
 }
 
 func (crs *ctxReportSuite) TestCtxReport_Success() {
-	crs.ctx = CtxNew(crs.ctx, crs.name, crs.logger)
+	crs.ctx = CtxNew(crs.ctx, crs.name, crs.logger, nil)
 
 	_ = CtxStart(crs.ctx)
 	_ = CtxStop(crs.ctx)
@@ -788,7 +1543,7 @@ func (crs *ctxReportSuite) TestCtxReport_Success() {
 }
 
 func (crs *ctxReportSuite) TestCtxReport_Error_NotStarted() {
-	crs.ctx = CtxNew(crs.ctx, crs.name, crs.logger)
+	crs.ctx = CtxNew(crs.ctx, crs.name, crs.logger, nil)
 	rpt, err := CtxReport(crs.ctx)
 	assert.Zero(crs.T(), rpt)
 	assert.NotNil(crs.T(), err)
@@ -801,7 +1556,7 @@ func (crs *ctxReportSuite) TestCtxReport_Error_NotStarted() {
 }
 
 func (crs *ctxReportSuite) TestCtxReport_Error_NotStopped() {
-	crs.ctx = CtxNew(crs.ctx, crs.name, crs.logger)
+	crs.ctx = CtxNew(crs.ctx, crs.name, crs.logger, nil)
 	_ = CtxStart(crs.ctx)
 
 	rpt, err := CtxReport(crs.ctx)
@@ -824,3 +1579,102 @@ func (crs *ctxReportSuite) TestCtxReport_Error_NotFound() {
 	expectedErr := NewNotFoundErr()
 	assert.Equal(crs.T(), expectedErr.Error(), err.Error())
 }
+
+//Success
+//Error
+// - NotFound
+
+func TestCtxSnapshot(t *testing.T) {
+	css := new(ctxSnapshotSuite)
+	suite.Run(t, css)
+}
+
+type ctxSnapshotSuite struct {
+	name string
+	ctxSuite
+}
+
+func (css *ctxSnapshotSuite) SetupTest() {
+	css.name = "test"
+	css.ctxSuite.SetupTest()
+}
+
+func (css *ctxSnapshotSuite) TestCtxSnapshot_Success() {
+	clock := NewManualClock(time.Now())
+	css.ctx = CtxNew(css.ctx, css.name, css.logger, clock)
+	_ = CtxStart(css.ctx)
+	clock.Advance(time.Second)
+
+	elapsed, err := CtxElapsed(css.ctx)
+	assert.Nil(css.T(), err)
+	assert.Equal(css.T(), time.Second, elapsed)
+
+	rpt, err := CtxSnapshot(css.ctx)
+	assert.Nil(css.T(), err)
+	assert.Equal(css.T(), time.Second, rpt.Duration)
+	assert.Equal(css.T(), 1, len(rpt.Splits))
+}
+
+func (css *ctxSnapshotSuite) TestCtxSnapshot_Error_NotFound() {
+	ctx := context.Background()
+	expectedErr := NewNotFoundErr()
+
+	elapsed, err := CtxElapsed(ctx)
+	assert.Zero(css.T(), elapsed)
+	assert.NotNil(css.T(), err)
+	assert.Equal(css.T(), expectedErr.Error(), err.Error())
+
+	rpt, err := CtxSnapshot(ctx)
+	assert.Zero(css.T(), rpt)
+	assert.NotNil(css.T(), err)
+	assert.Equal(css.T(), expectedErr.Error(), err.Error())
+}
+
+//Success
+//Error
+// - NotFound
+
+func TestCtxChild(t *testing.T) {
+	ccs := new(ctxChildSuite)
+	suite.Run(t, ccs)
+}
+
+type ctxChildSuite struct {
+	name string
+	ctxSuite
+}
+
+func (ccs *ctxChildSuite) SetupTest() {
+	ccs.name = "test"
+	ccs.ctxSuite.SetupTest()
+}
+
+func (ccs *ctxChildSuite) TestCtxChild_Success() {
+	ccs.ctx = CtxNew(ccs.ctx, ccs.name, ccs.logger, nil)
+	parent, _ := getStopwatchFromCtx(ccs.ctx)
+
+	childCtx, err := CtxChild(ccs.ctx, "db")
+	assert.Nil(ccs.T(), err)
+	child, err := getStopwatchFromCtx(childCtx)
+	assert.Nil(ccs.T(), err)
+	assert.Equal(ccs.T(), "db", child.Name)
+	assert.Equal(ccs.T(), parent, child.Parent())
+
+	_ = CtxStart(ccs.ctx)
+	_ = CtxStart(childCtx)
+	_ = CtxStop(childCtx)
+	_ = CtxStop(ccs.ctx)
+
+	rpt, err := CtxReport(ccs.ctx)
+	assert.Nil(ccs.T(), err)
+	assert.Equal(ccs.T(), 1, len(rpt.Children))
+	assert.Equal(ccs.T(), "db", rpt.Children[0].Name)
+}
+
+func (ccs *ctxChildSuite) TestCtxChild_Error_NotFound() {
+	ctx := context.Background()
+	childCtx, err := CtxChild(ctx, "db")
+	assert.NotNil(ccs.T(), err)
+	assert.Equal(ccs.T(), ctx, childCtx)
+	assert.Equal(ccs.T(), NewNotFoundErr().Error(), err.Error())
+}
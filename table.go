@@ -0,0 +1,97 @@
+package stopwatch
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+type SortOrder int
+
+const (
+	SortByOrder SortOrder = iota
+	SortByDuration
+)
+
+//TableOptions.Unit fixes every duration to one unit (e.g. time.Millisecond);
+//zero keeps time.Duration's own formatting.
+type TableOptions struct {
+	Unit time.Duration
+	Sort SortOrder
+}
+
+type tableRow struct {
+	split      Split
+	cumulative time.Duration
+}
+
+var unitSuffixes = map[time.Duration]string{
+	time.Nanosecond:  "ns",
+	time.Microsecond: "µs",
+	time.Millisecond: "ms",
+	time.Second:      "s",
+	time.Minute:      "m",
+	time.Hour:        "h",
+}
+
+func WriteTable(w io.Writer, r Report, opts TableOptions) error {
+	rows := make([]tableRow, len(r.Splits))
+	var cumulative time.Duration
+	for i, split := range r.Splits {
+		cumulative += split.Duration
+		rows[i] = tableRow{
+			split:      split,
+			cumulative: cumulative,
+		}
+	}
+
+	if opts.Sort == SortByDuration {
+		sort.SliceStable(rows, func(i, j int) bool {
+			return rows[i].split.Duration > rows[j].split.Duration
+		})
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SPLIT\tCOMMENT\tDURATION\t%\tCUMULATIVE")
+	for _, row := range rows {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n",
+			row.split.Name,
+			row.split.Comment,
+			formatDuration(row.split.Duration, opts.Unit),
+			formatPercent(row.split.Duration, r.Duration),
+			formatDuration(row.cumulative, opts.Unit),
+		)
+	}
+	fmt.Fprintf(tw, "%s\t\t%s\t%s\n", "TOTAL", formatDuration(r.Duration, opts.Unit), formatPercent(r.Duration, r.Duration))
+	return tw.Flush()
+}
+
+func (r Report) String() string {
+	buf := &bytes.Buffer{}
+	_ = WriteTable(buf, r, TableOptions{})
+	return buf.String()
+}
+
+func formatDuration(d time.Duration, unit time.Duration) string {
+	if unit <= 0 {
+		return d.String()
+	}
+
+	suffix, known := unitSuffixes[unit]
+	if !known {
+		suffix = "x" + unit.String()
+	}
+
+	return fmt.Sprintf("%.3f%s", float64(d)/float64(unit), suffix)
+}
+
+func formatPercent(part, total time.Duration) string {
+	if total <= 0 {
+		return "0.0%"
+	}
+
+	return fmt.Sprintf("%.1f%%", 100*float64(part)/float64(total))
+}
@@ -0,0 +1,85 @@
+package stopwatch
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"strings"
+	"testing"
+	"time"
+)
+
+//Success
+// - Default options
+// - SortByDuration
+// - Unit
+// - Empty report
+// - String
+func TestWriteTable(t *testing.T) {
+	ts := new(tableSuite)
+	suite.Run(t, ts)
+}
+
+type tableSuite struct {
+	rpt Report
+	buf *bytes.Buffer
+	suite.Suite
+}
+
+func (ts *tableSuite) SetupTest() {
+	ts.rpt = fixtureReports(1)[0]
+	ts.buf = &bytes.Buffer{}
+}
+
+func (ts *tableSuite) TestWriteTable_Success() {
+	err := WriteTable(ts.buf, ts.rpt, TableOptions{})
+	assert.Nil(ts.T(), err)
+
+	expected := strings.Join([]string{
+		"SPLIT   COMMENT      DURATION  %      CUMULATIVE",
+		"start                1ms       10.0%  1ms",
+		"db      users table  7ms       70.0%  8ms",
+		"render               2ms       20.0%  10ms",
+		"TOTAL                10ms      100.0%",
+		"",
+	}, "\n")
+	assert.Equal(ts.T(), expected, ts.buf.String())
+}
+
+func (ts *tableSuite) TestWriteTable_Success_SortByDuration() {
+	err := WriteTable(ts.buf, ts.rpt, TableOptions{Sort: SortByDuration})
+	assert.Nil(ts.T(), err)
+
+	lines := strings.Split(ts.buf.String(), "\n")
+	assert.True(ts.T(), strings.HasPrefix(lines[1], "db "))
+	assert.True(ts.T(), strings.HasPrefix(lines[2], "render "))
+	assert.True(ts.T(), strings.HasPrefix(lines[3], "start "))
+	//cumulative offsets stay chronological when sorted
+	assert.True(ts.T(), strings.HasSuffix(lines[1], " 8ms"))
+}
+
+func (ts *tableSuite) TestWriteTable_Success_Unit() {
+	err := WriteTable(ts.buf, ts.rpt, TableOptions{Unit: time.Microsecond})
+	assert.Nil(ts.T(), err)
+	assert.Contains(ts.T(), ts.buf.String(), "7000.000µs")
+	assert.Contains(ts.T(), ts.buf.String(), "10000.000µs")
+}
+
+func (ts *tableSuite) TestWriteTable_Success_Empty() {
+	err := WriteTable(ts.buf, Report{}, TableOptions{})
+	assert.Nil(ts.T(), err)
+	assert.Contains(ts.T(), ts.buf.String(), "TOTAL")
+	assert.Contains(ts.T(), ts.buf.String(), "0.0%")
+}
+
+func (ts *tableSuite) TestReportString() {
+	_ = WriteTable(ts.buf, ts.rpt, TableOptions{})
+	assert.Equal(ts.T(), ts.buf.String(), ts.rpt.String())
+}
+
+func TestFormatDuration(t *testing.T) {
+	assert.Equal(t, "1.5ms", formatDuration(1500*time.Microsecond, 0))
+	assert.Equal(t, "1.500ms", formatDuration(1500*time.Microsecond, time.Millisecond))
+	assert.Equal(t, "0.025s", formatDuration(25*time.Millisecond, time.Second))
+	assert.Equal(t, "3.000x10ms", formatDuration(30*time.Millisecond, 10*time.Millisecond))
+}
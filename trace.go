@@ -0,0 +1,187 @@
+package stopwatch
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+)
+
+const tracePid = 1
+
+type traceEvent struct {
+	Name  string                 `json:"name"`
+	Cat   string                 `json:"cat,omitempty"`
+	Ph    string                 `json:"ph"`
+	Ts    float64                `json:"ts"`
+	Dur   *float64               `json:"dur,omitempty"`
+	Pid   int                    `json:"pid"`
+	Tid   int                    `json:"tid"`
+	ID    string                 `json:"id,omitempty"`
+	Scope string                 `json:"s,omitempty"`
+	Args  map[string]interface{} `json:"args,omitempty"`
+}
+
+type traceFile struct {
+	TraceEvents     []traceEvent `json:"traceEvents"`
+	DisplayTimeUnit string       `json:"displayTimeUnit"`
+}
+
+type tracer struct {
+	origin time.Time
+	tracks map[string]int
+	events []traceEvent
+	nextID int
+}
+
+//WriteTrace emits Trace Event Format JSON for chrome://tracing and Perfetto.
+//Every stopwatch name gets its own track; reports without timestamps are
+//skipped since they cannot be placed on the timeline.
+func WriteTrace(w io.Writer, reports ...Report) error {
+	t := &tracer{
+		tracks: make(map[string]int),
+		events: make([]traceEvent, 0),
+	}
+	for _, rpt := range reports {
+		t.findOrigin(rpt)
+	}
+	for _, rpt := range reports {
+		t.addReport(rpt)
+	}
+
+	return json.NewEncoder(w).Encode(traceFile{
+		TraceEvents:     t.events,
+		DisplayTimeUnit: "ns",
+	})
+}
+
+func (t *tracer) findOrigin(r Report) {
+	if !r.StartedAt.IsZero() && (t.origin.IsZero() || r.StartedAt.Before(t.origin)) {
+		t.origin = r.StartedAt
+	}
+	for _, child := range r.Children {
+		t.findOrigin(child)
+	}
+}
+
+func (t *tracer) addReport(r Report) {
+	if r.StartedAt.IsZero() {
+		return
+	}
+
+	tid := t.track(r.Name)
+	t.complete(tid, "stopwatch", r.Name, r.StartedAt, reportEnd(r), map[string]interface{}{
+		"duration_ns": int64(r.Duration),
+		"paused_ns":   int64(r.PausedDuration),
+	})
+
+	for i, split := range r.Splits {
+		t.complete(tid, "split", split.Name, split.Start, split.End, map[string]interface{}{
+			"comment":     split.Comment,
+			"duration_ns": int64(split.Duration),
+		})
+		if i > 0 {
+			t.events = append(t.events, traceEvent{
+				Name:  split.Name,
+				Cat:   "lap",
+				Ph:    "i",
+				Ts:    t.micros(split.Start),
+				Pid:   tracePid,
+				Tid:   tid,
+				Scope: "t",
+				Args:  map[string]interface{}{"comment": split.Comment},
+			})
+		}
+	}
+
+	//pauses, branches and intervals may overlap splits without nesting, so
+	//they go out as async events which viewers stack separately
+	for _, p := range r.Pauses {
+		t.async(tid, "pause", p)
+	}
+	for _, b := range r.Branches {
+		t.async(tid, "branch", b)
+	}
+	for _, i := range r.Intervals {
+		t.async(tid, "interval", i)
+	}
+
+	for _, child := range r.Children {
+		t.addReport(child)
+	}
+}
+
+func (t *tracer) track(name string) int {
+	tid, exists := t.tracks[name]
+	if exists {
+		return tid
+	}
+
+	tid = len(t.tracks) + 1
+	t.tracks[name] = tid
+	t.events = append(t.events, traceEvent{
+		Name: "thread_name",
+		Ph:   "M",
+		Pid:  tracePid,
+		Tid:  tid,
+		Args: map[string]interface{}{"name": name},
+	})
+	return tid
+}
+
+func (t *tracer) complete(tid int, cat, name string, begin, end time.Time, args map[string]interface{}) {
+	dur := float64(end.Sub(begin)) / float64(time.Microsecond)
+	t.events = append(t.events, traceEvent{
+		Name: name,
+		Cat:  cat,
+		Ph:   "X",
+		Ts:   t.micros(begin),
+		Dur:  &dur,
+		Pid:  tracePid,
+		Tid:  tid,
+		Args: args,
+	})
+}
+
+func (t *tracer) async(tid int, cat string, i Interval) {
+	t.nextID++
+	id := strconv.Itoa(t.nextID)
+	t.events = append(t.events,
+		traceEvent{
+			Name: i.Name,
+			Cat:  cat,
+			Ph:   "b",
+			Ts:   t.micros(i.Start),
+			Pid:  tracePid,
+			Tid:  tid,
+			ID:   id,
+			Args: map[string]interface{}{"duration_ns": int64(i.Duration)},
+		},
+		traceEvent{
+			Name: i.Name,
+			Cat:  cat,
+			Ph:   "e",
+			Ts:   t.micros(i.End),
+			Pid:  tracePid,
+			Tid:  tid,
+			ID:   id,
+		},
+	)
+}
+
+func (t *tracer) micros(ts time.Time) float64 {
+	return float64(ts.Sub(t.origin)) / float64(time.Microsecond)
+}
+
+//reportEnd is StoppedAt, or the end of the last split for a snapshot.
+func reportEnd(r Report) time.Time {
+	if !r.StoppedAt.IsZero() {
+		return r.StoppedAt
+	}
+
+	if len(r.Splits) == 0 {
+		return r.StartedAt
+	}
+
+	return r.Splits[len(r.Splits)-1].End
+}
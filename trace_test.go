@@ -0,0 +1,135 @@
+package stopwatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"testing"
+)
+
+func eventsByPh(tf traceFile, ph string) []traceEvent {
+	events := make([]traceEvent, 0)
+	for _, e := range tf.TraceEvents {
+		if e.Ph == ph {
+			events = append(events, e)
+		}
+	}
+
+	return events
+}
+
+//Success
+// - One track per stopwatch name
+// - Runs and splits as complete events
+// - Laps, pauses and intervals
+// - No reports
+func TestWriteTrace(t *testing.T) {
+	ts := new(traceSuite)
+	suite.Run(t, ts)
+}
+
+type traceSuite struct {
+	reports []Report
+	suite.Suite
+}
+
+func (ts *traceSuite) SetupTest() {
+	ts.reports = fixtureReports(2)
+	ts.reports[1].Name = "worker"
+	ts.reports = append(ts.reports, Report{Name: "untimed"})
+}
+
+func (ts *traceSuite) decodeTrace(reports ...Report) traceFile {
+	buf := &bytes.Buffer{}
+	err := WriteTrace(buf, reports...)
+	assert.Nil(ts.T(), err)
+
+	var tf traceFile
+	err = json.Unmarshal(buf.Bytes(), &tf)
+	assert.Nil(ts.T(), err)
+	return tf
+}
+
+func (ts *traceSuite) TestWriteTrace_Success_Tracks() {
+	tf := ts.decodeTrace(ts.reports...)
+	assert.Equal(ts.T(), "ns", tf.DisplayTimeUnit)
+
+	tracks := eventsByPh(tf, "M")
+	assert.Equal(ts.T(), 3, len(tracks))
+	names := make(map[string]int)
+	for _, e := range tracks {
+		assert.Equal(ts.T(), "thread_name", e.Name)
+		names[e.Args["name"].(string)] = e.Tid
+	}
+	assert.Equal(ts.T(), map[string]int{"handler": 1, "db": 2, "worker": 3}, names)
+}
+
+func (ts *traceSuite) TestWriteTrace_Success_CompleteEvents() {
+	tf := ts.decodeTrace(ts.reports...)
+	complete := eventsByPh(tf, "X")
+
+	//each run and its child: run + 3 splits, child run + 2 splits
+	assert.Equal(ts.T(), 14, len(complete))
+
+	handler := complete[0]
+	assert.Equal(ts.T(), "handler", handler.Name)
+	assert.Equal(ts.T(), "stopwatch", handler.Cat)
+	assert.Equal(ts.T(), 0.0, handler.Ts)
+	assert.Equal(ts.T(), 11000.0, *handler.Dur)
+	assert.Equal(ts.T(), 1e7, handler.Args["duration_ns"])
+	assert.Equal(ts.T(), 1e6, handler.Args["paused_ns"])
+
+	dbSplit := complete[2]
+	assert.Equal(ts.T(), "db", dbSplit.Name)
+	assert.Equal(ts.T(), "split", dbSplit.Cat)
+	assert.Equal(ts.T(), 1000.0, dbSplit.Ts)
+	assert.Equal(ts.T(), 8000.0, *dbSplit.Dur)
+	assert.Equal(ts.T(), "users table", dbSplit.Args["comment"])
+	assert.Equal(ts.T(), 1, dbSplit.Tid)
+
+	dbRun := complete[4]
+	assert.Equal(ts.T(), "db", dbRun.Name)
+	assert.Equal(ts.T(), "stopwatch", dbRun.Cat)
+	assert.Equal(ts.T(), 2, dbRun.Tid)
+	assert.Equal(ts.T(), 8000.0, *dbRun.Dur)
+
+	worker := complete[7]
+	assert.Equal(ts.T(), "worker", worker.Name)
+	assert.Equal(ts.T(), 11000.0, worker.Ts)
+	assert.Equal(ts.T(), 3, worker.Tid)
+
+	//both runs' children share the db track
+	assert.Equal(ts.T(), 2, complete[11].Tid)
+	assert.Equal(ts.T(), 12000.0, complete[11].Ts)
+}
+
+func (ts *traceSuite) TestWriteTrace_Success_LapsAndAsyncEvents() {
+	tf := ts.decodeTrace(ts.reports[0])
+
+	laps := eventsByPh(tf, "i")
+	assert.Equal(ts.T(), 3, len(laps))
+	assert.Equal(ts.T(), "db", laps[0].Name)
+	assert.Equal(ts.T(), "lap", laps[0].Cat)
+	assert.Equal(ts.T(), "t", laps[0].Scope)
+	assert.Equal(ts.T(), 1000.0, laps[0].Ts)
+	assert.Equal(ts.T(), "query;select", laps[2].Name)
+	assert.Equal(ts.T(), 2, laps[2].Tid)
+
+	begins, ends := eventsByPh(tf, "b"), eventsByPh(tf, "e")
+	assert.Equal(ts.T(), 2, len(begins))
+	assert.Equal(ts.T(), len(begins), len(ends))
+	assert.Equal(ts.T(), "pause", begins[0].Cat)
+	assert.Equal(ts.T(), 5000.0, begins[0].Ts)
+	assert.Equal(ts.T(), 6000.0, ends[0].Ts)
+	assert.Equal(ts.T(), "request", begins[1].Name)
+	assert.Equal(ts.T(), "interval", begins[1].Cat)
+	assert.Equal(ts.T(), 11000.0, ends[1].Ts)
+	assert.NotEqual(ts.T(), begins[0].ID, begins[1].ID)
+	assert.Equal(ts.T(), begins[1].ID, ends[1].ID)
+}
+
+func (ts *traceSuite) TestWriteTrace_Success_Empty() {
+	tf := ts.decodeTrace()
+	assert.Empty(ts.T(), tf.TraceEvents)
+}
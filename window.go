@@ -0,0 +1,136 @@
+package stopwatch
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+//RollingWindow answers "over the last window" questions per split name by
+//keeping a DigestSet per bucketWidth slice of time; whole buckets expire, so
+//the window edge is only as sharp as bucketWidth.
+type RollingWindow struct {
+	window      time.Duration
+	bucketWidth time.Duration
+	Clock       Clock
+	Compression float64
+
+	buckets map[int64]*DigestSet
+
+	rl *sync.Mutex
+}
+
+func NewRollingWindow(window, bucketWidth time.Duration, clock Clock) (*RollingWindow, error) {
+	if bucketWidth <= 0 {
+		return nil, NewInvalidWindowErr("bucket width must be positive")
+	}
+	if window < bucketWidth {
+		return nil, NewInvalidWindowErr("window must be at least one bucket wide")
+	}
+	if clock == nil {
+		clock = &systemClock{}
+	}
+
+	return &RollingWindow{
+		window:      window,
+		bucketWidth: bucketWidth,
+		Clock:       clock,
+		Compression: DefaultCompression,
+
+		buckets: make(map[int64]*DigestSet),
+
+		rl: &sync.Mutex{},
+	}, nil
+}
+
+//Add files r under its StoppedAt, or the clock's now for a Snapshot; reports
+//already older than the window are dropped.
+func (rw *RollingWindow) Add(r Report) {
+	rw.rl.Lock()
+	defer rw.rl.Unlock()
+
+	now := rw.Clock.Now()
+	at := r.StoppedAt
+	if at.IsZero() {
+		at = now
+	}
+	rw.expire(now)
+
+	start := at.Truncate(rw.bucketWidth)
+	if !rw.live(start, now) {
+		return
+	}
+	bucket, ok := rw.buckets[start.UnixNano()]
+	if !ok {
+		bucket = NewDigestSet(rw.Compression)
+		rw.buckets[start.UnixNano()] = bucket
+	}
+	bucket.Record(r)
+}
+
+//Count is the number of times the split was recorded within the window.
+func (rw *RollingWindow) Count(name string) int64 {
+	d := rw.digest(name)
+	return d.Count()
+}
+
+//Rate is Count per second over the full window length, so it reads low
+//until the window has been filled once.
+func (rw *RollingWindow) Rate(name string) float64 {
+	return float64(rw.Count(name)) / rw.window.Seconds()
+}
+
+//Quantile returns 0 when the split has no data within the window.
+func (rw *RollingWindow) Quantile(name string, q float64) time.Duration {
+	d := rw.digest(name)
+	return d.QuantileDuration(q)
+}
+
+//Names lists the split names with data in the window, sorted.
+func (rw *RollingWindow) Names() []string {
+	rw.rl.Lock()
+	defer rw.rl.Unlock()
+
+	rw.expire(rw.Clock.Now())
+	seen := make(map[string]bool)
+	names := make([]string, 0)
+	for _, bucket := range rw.buckets {
+		for _, name := range bucket.Names() {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+//digest merges the split's digest from every live bucket.
+func (rw *RollingWindow) digest(name string) *Digest {
+	rw.rl.Lock()
+	defer rw.rl.Unlock()
+
+	rw.expire(rw.Clock.Now())
+	merged := NewDigest(rw.Compression)
+	for _, bucket := range rw.buckets {
+		if d, ok := bucket.Digest(name); ok {
+			merged.Merge(d)
+		}
+	}
+	return merged
+}
+
+func (rw *RollingWindow) expire(now time.Time) {
+	for start := range rw.buckets {
+		if !rw.live(time.Unix(0, start), now) {
+			delete(rw.buckets, start)
+		}
+	}
+}
+
+//live reports whether a bucket starting at start still overlaps the window
+//ending at now.
+func (rw *RollingWindow) live(start, now time.Time) bool {
+	return start.Add(rw.bucketWidth).After(now.Add(-rw.window))
+}
@@ -0,0 +1,116 @@
+package stopwatch
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+	"sync"
+	"testing"
+	"time"
+)
+
+//Success
+// - Buckets age out of the window
+// - Reports are filed under StoppedAt
+// - Snapshots are filed under the clock's now
+// - Concurrent add and query
+//Error
+// - Invalid window
+func TestRollingWindow(t *testing.T) {
+	ws := new(windowSuite)
+	suite.Run(t, ws)
+}
+
+type windowSuite struct {
+	clock   *ManualClock
+	rw      *RollingWindow
+	reports []Report
+	suite.Suite
+}
+
+func (ws *windowSuite) SetupTest() {
+	ws.clock = NewManualClock(fixtureStart.Add(time.Second))
+	ws.rw, _ = NewRollingWindow(5*time.Minute, time.Minute, ws.clock)
+	ws.reports = fixtureReports(4)
+}
+
+func (ws *windowSuite) TestRollingWindow_Success() {
+	for _, rpt := range ws.reports {
+		ws.rw.Add(rpt)
+	}
+	ws.clock.Advance(2 * time.Minute)
+	for _, rpt := range ws.reports {
+		rpt.StoppedAt = rpt.StoppedAt.Add(2 * time.Minute)
+		rpt.Splits = []Split{{Name: "db", Duration: time.Second}}
+		ws.rw.Add(rpt)
+	}
+
+	assert.Equal(ws.T(), []string{"db", "render", "start"}, ws.rw.Names())
+	assert.Equal(ws.T(), int64(8), ws.rw.Count("db"))
+	assert.InDelta(ws.T(), 8.0/300.0, ws.rw.Rate("db"), 1e-9)
+	assert.Equal(ws.T(), 7*time.Millisecond, ws.rw.Quantile("db", 0))
+	assert.Equal(ws.T(), time.Second, ws.rw.Quantile("db", 1))
+
+	//the first minute's bucket ages out, the third's is still live
+	ws.clock.Advance(4 * time.Minute)
+	assert.Equal(ws.T(), int64(4), ws.rw.Count("db"))
+	assert.Zero(ws.T(), ws.rw.Count("render"))
+	assert.Equal(ws.T(), time.Second, ws.rw.Quantile("db", 0.5))
+
+	ws.clock.Advance(2 * time.Minute)
+	assert.Zero(ws.T(), ws.rw.Count("db"))
+	assert.Zero(ws.T(), ws.rw.Quantile("db", 0.5))
+	assert.Empty(ws.T(), ws.rw.Names())
+}
+
+func (ws *windowSuite) TestRollingWindow_Success_UsesStoppedAt() {
+	rw, _ := NewRollingWindow(time.Minute, 10*time.Second, ws.clock)
+	rpt := ws.reports[0]
+
+	rpt.StoppedAt = ws.clock.Now().Add(-2 * time.Minute)
+	rw.Add(rpt)
+	assert.Zero(ws.T(), rw.Count("db"))
+
+	rpt.StoppedAt = ws.clock.Now().Add(-30 * time.Second)
+	rw.Add(rpt)
+	assert.Equal(ws.T(), int64(1), rw.Count("db"))
+
+	ws.clock.Advance(40 * time.Second)
+	assert.Zero(ws.T(), rw.Count("db"))
+}
+
+func (ws *windowSuite) TestRollingWindow_Success_SnapshotUsesClock() {
+	rpt := ws.reports[0]
+	rpt.StoppedAt = time.Time{}
+	ws.clock.Advance(time.Hour)
+
+	ws.rw.Add(rpt)
+	assert.Equal(ws.T(), int64(1), ws.rw.Count("db"))
+	assert.Zero(ws.T(), ws.rw.Count("missing"))
+}
+
+func (ws *windowSuite) TestRollingWindow_Success_Concurrency() {
+	rw, _ := NewRollingWindow(time.Hour, time.Minute, nil)
+	rpt := ws.reports[0]
+	rpt.StoppedAt = time.Time{}
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				rw.Add(rpt)
+				rw.Quantile("db", 0.99)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(ws.T(), int64(800), rw.Count("db"))
+}
+
+func (ws *windowSuite) TestNewRollingWindow_Error() {
+	_, err := NewRollingWindow(time.Minute, 0, nil)
+	assert.Equal(ws.T(), NewInvalidWindowErr("bucket width must be positive").Error(), err.Error())
+
+	_, err = NewRollingWindow(time.Second, time.Minute, nil)
+	assert.Equal(ws.T(), NewInvalidWindowErr("window must be at least one bucket wide").Error(), err.Error())
+}